@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/albantanie/mahfudzot-generator/internal/auth"
+	"github.com/albantanie/mahfudzot-generator/internal/config"
+	"github.com/albantanie/mahfudzot-generator/internal/database"
+)
+
+func main() {
+	var (
+		create = flag.Bool("create", false, "Mint a new API key")
+		name   = flag.String("name", "", "Name of the key owner (with -create)")
+		role   = flag.String("role", string(auth.RoleReader), "Role of the new key: reader, editor or admin (with -create)")
+		list   = flag.Bool("list", false, "List all API keys")
+		revoke = flag.Int("revoke", 0, "Revoke the API key with the given ID")
+		help   = flag.Bool("help", false, "Show help")
+	)
+	flag.Parse()
+
+	if *help || (!*create && !*list && *revoke == 0) {
+		showHelp()
+		return
+	}
+
+	ctx := context.Background()
+
+	// Load configuration
+	cfg := config.Load()
+
+	// Connect to database
+	db, err := database.New(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	switch {
+	case *create:
+		createKey(ctx, db, *name, *role)
+	case *list:
+		listKeys(ctx, db)
+	case *revoke != 0:
+		if err := db.RevokeAPIKey(ctx, *revoke); err != nil {
+			log.Fatalf("Failed to revoke API key: %v", err)
+		}
+		log.Printf("API key %d revoked", *revoke)
+	}
+}
+
+func createKey(ctx context.Context, db *database.DB, name, roleName string) {
+	if name == "" {
+		log.Fatal("The -name flag is required with -create")
+	}
+
+	role, err := auth.ParseRole(roleName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	key, err := auth.GenerateKey()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	record, err := db.CreateAPIKey(ctx, name, string(role), auth.HashKey(key), auth.KeyPrefix(key))
+	if err != nil {
+		log.Fatalf("Failed to store API key: %v", err)
+	}
+
+	log.Printf("Created %s key %d for %s", record.Role, record.ID, record.Name)
+	log.Println("Store this key now, it cannot be shown again:")
+	fmt.Println(key)
+}
+
+func listKeys(ctx context.Context, db *database.DB) {
+	keys, err := db.ListAPIKeys(ctx)
+	if err != nil {
+		log.Fatalf("Failed to list API keys: %v", err)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tPREFIX\tROLE\tCREATED\tSTATUS")
+	for _, key := range keys {
+		status := "active"
+		if key.RevokedAt != nil {
+			status = "revoked " + key.RevokedAt.Format("2006-01-02")
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			key.ID, key.Name, key.Prefix, key.Role, key.CreatedAt.Format("2006-01-02"), status)
+	}
+	tw.Flush()
+}
+
+func showHelp() {
+	log.Println("Mahfudzot Generator API Key Manager")
+	log.Println("")
+	log.Println("Usage:")
+	log.Printf("  %s -create -name <owner> [-role reader|editor|admin]\n", os.Args[0])
+	log.Printf("  %s -list\n", os.Args[0])
+	log.Printf("  %s -revoke <id>\n", os.Args[0])
+	log.Println("")
+	log.Println("Roles:")
+	log.Println("  reader    Read quotes")
+	log.Println("  editor    Read, create and update quotes")
+	log.Println("  admin     Everything, including deleting quotes")
+	log.Println("")
+	log.Println("Uses the same DB_* environment variables as the API server.")
+}
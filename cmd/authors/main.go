@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/albantanie/mahfudzot-generator/internal/config"
+	"github.com/albantanie/mahfudzot-generator/internal/database"
+	"github.com/albantanie/mahfudzot-generator/internal/models"
+)
+
+func main() {
+	var (
+		merge   = flag.Int("merge", 0, "Merge the author with the given ID into the author given by -into")
+		into    = flag.Int("into", 0, "ID of the author to keep (with -merge)")
+		resolve = flag.String("resolve", "", "Show the author with the given name or alias")
+		help    = flag.Bool("help", false, "Show help")
+	)
+	flag.Parse()
+
+	if *help || (*merge == 0 && *resolve == "") {
+		showHelp()
+		return
+	}
+
+	ctx := context.Background()
+
+	// Load configuration
+	cfg := config.Load()
+
+	// Connect to database
+	db, err := database.New(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	switch {
+	case *merge != 0:
+		if *into == 0 {
+			log.Fatal("The -into flag is required with -merge")
+		}
+		author, err := db.MergeAuthors(ctx, *merge, *into)
+		if err != nil {
+			log.Fatalf("Failed to merge authors: %v", err)
+		}
+		log.Printf("Merged author %d into %d", *merge, author.ID)
+		printAuthor(author)
+	case *resolve != "":
+		author, err := db.ResolveAuthor(ctx, *resolve)
+		if err != nil {
+			log.Fatalf("Failed to resolve author: %v", err)
+		}
+		printAuthor(author)
+	}
+}
+
+func printAuthor(author *models.Author) {
+	fmt.Printf("ID:      %d\n", author.ID)
+	fmt.Printf("Name:    %s\n", author.Name)
+	fmt.Printf("Aliases: %s\n", strings.Join(author.Aliases, ", "))
+	fmt.Printf("Quotes:  %d\n", author.QuoteCount)
+}
+
+func showHelp() {
+	log.Println("Mahfudzot Generator Author Manager")
+	log.Println("")
+	log.Println("Usage:")
+	log.Printf("  %s -merge <id> -into <id>\n", os.Args[0])
+	log.Printf("  %s -resolve <name>\n", os.Args[0])
+	log.Println("")
+	log.Println("Merging moves all quotes and aliases of the first author to the second,")
+	log.Println("deletes the first author and records its name as an alias.")
+	log.Println("")
+	log.Println("Uses the same DB_* environment variables as the API server.")
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
 	"os"
@@ -21,6 +22,8 @@ func main() {
 		return
 	}
 
+	ctx := context.Background()
+
 	// Load configuration
 	cfg := config.Load()
 
@@ -33,7 +36,7 @@ func main() {
 
 	// Check if data already exists
 	if !*force {
-		count, err := db.Count()
+		count, err := db.Count(ctx, database.QuoteFilter{})
 		if err != nil {
 			log.Fatalf("Failed to check existing data: %v", err)
 		}
@@ -46,13 +49,13 @@ func main() {
 
 	// Run seeder
 	log.Println("Starting database seeding...")
-	err = database.SeedDatabase(db)
+	err = database.SeedDatabase(ctx, db)
 	if err != nil {
 		log.Fatalf("Failed to seed database: %v", err)
 	}
 
 	// Verify seeding
-	count, err := db.Count()
+	count, err := db.Count(ctx, database.QuoteFilter{})
 	if err != nil {
 		log.Printf("Warning: Failed to verify seeding: %v", err)
 	} else {
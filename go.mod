@@ -2,6 +2,8 @@ module github.com/albantanie/mahfudzot-generator
 
 go 1.22.1
 
-require github.com/gorilla/mux v1.8.1
-
-require github.com/lib/pq v1.10.9 // indirect
+require (
+	github.com/gorilla/mux v1.8.1
+	github.com/lib/pq v1.10.9
+	golang.org/x/text v0.21.0
+)
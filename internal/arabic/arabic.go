@@ -0,0 +1,117 @@
+// Package arabic provides the Arabic text handling shared by search,
+// deduplication and validation: diacritic and tatweel removal, letter-variant
+// folding, Unicode NFC normalization, digit conversion and tokenization.
+package arabic
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Tatweel is the kashida used to stretch words, which carries no meaning
+const Tatweel = 'ـ'
+
+// letterVariants maps letter forms that are treated as equivalent.
+// Keep in sync with normalize_arabic() in migrations/012_normalize_search_text_nfc.sql.
+var letterVariants = map[rune]rune{
+	'أ': 'ا', // alef with hamza above
+	'إ': 'ا', // alef with hamza below
+	'آ': 'ا', // alef with madda
+	'ٱ': 'ا', // alef wasla
+	'ؤ': 'و', // waw with hamza
+	'ئ': 'ي', // yaa with hamza
+	'ة': 'ه', // taa marbuta
+	'ى': 'ي', // alef maqsura
+}
+
+// IsDiacritic reports whether r is tashkeel, a superscript alef or a Quranic
+// annotation mark
+func IsDiacritic(r rune) bool {
+	return (r >= 0x064B && r <= 0x065F) || r == 0x0670 || (r >= 0x06D6 && r <= 0x06ED)
+}
+
+// IsLetter reports whether r is an Arabic-script letter
+func IsLetter(r rune) bool {
+	return unicode.Is(unicode.Arabic, r) && unicode.IsLetter(r)
+}
+
+// ContainsArabic reports whether s has at least one Arabic-script letter
+func ContainsArabic(s string) bool {
+	for _, r := range s {
+		if IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// NFC returns s in Unicode Normalization Form C
+func NFC(s string) string {
+	return norm.NFC.String(s)
+}
+
+// RemoveDiacritics strips tashkeel and Quranic annotation marks from s
+func RemoveDiacritics(s string) string {
+	return strings.Map(func(r rune) rune {
+		if IsDiacritic(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// RemoveTatweel strips tatweel from s
+func RemoveTatweel(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == Tatweel {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// NormalizeLetters unifies alef/hamza forms, taa marbuta and alef maqsura
+func NormalizeLetters(s string) string {
+	return strings.Map(func(r rune) rune {
+		if v, ok := letterVariants[r]; ok {
+			return v
+		}
+		return r
+	}, s)
+}
+
+// NormalizeDigits converts Arabic-Indic (٠-٩) and Persian (۰-۹) digits to
+// ASCII digits
+func NormalizeDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '٠' && r <= '٩':
+			return '0' + r - '٠'
+		case r >= '۰' && r <= '۹':
+			return '0' + r - '۰'
+		}
+		return r
+	}, s)
+}
+
+// Fold returns the comparison form of s: NFC-normalized, without diacritics
+// or tatweel, with letter variants unified and Latin text lower-cased. Two
+// strings that differ only in vocalization or spelling variants fold to the
+// same value.
+func Fold(s string) string {
+	s = NFC(s)
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if IsDiacritic(r) || r == Tatweel {
+			continue
+		}
+		if v, ok := letterVariants[r]; ok {
+			r = v
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
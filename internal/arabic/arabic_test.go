@@ -0,0 +1,183 @@
+package arabic_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/albantanie/mahfudzot-generator/internal/arabic"
+	"golang.org/x/text/unicode/norm"
+)
+
+// decorate adds a fatha after every Arabic letter of s and a tatweel after
+// the first letter of each word, the way vocalized or stretched copies of a
+// quote are typically entered
+func decorate(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		var b strings.Builder
+		first := true
+		for _, r := range word {
+			b.WriteRune(r)
+			if !arabic.IsLetter(r) {
+				continue
+			}
+			if first {
+				b.WriteRune(arabic.Tatweel)
+				first = false
+			}
+			b.WriteRune('\u064E') // fatha
+		}
+		words[i] = b.String()
+	}
+	return strings.Join(words, " ")
+}
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"العِلْمُ نُورٌ", "العلم نور"},
+		{"الصبـــر مفتاح الفرج", "الصبر مفتاح الفرج"},
+		{"إنما الأعمال بالنيات", "انما الاعمال بالنيات"},
+		{"الدنيا مزرعة الآخرة", "الدنيا مزرعه الاخره"},
+		{"الجهل يؤدي إلى الخوف", "الجهل يودي الي الخوف"},
+		{"على قدر أهل العزم تأتي العزائم", "علي قدر اهل العزم تاتي العزايم"},
+		{"ٱلْحَمْدُ لِلَّهِ", "الحمد لله"},
+		{"Al-'Ilmu Nur", "al-'ilmu nur"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := arabic.Fold(tt.in); got != tt.want {
+			t.Errorf("Fold(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFoldSamples(t *testing.T) {
+	for _, text := range samples {
+		folded := arabic.Fold(text)
+
+		if again := arabic.Fold(folded); again != folded {
+			t.Errorf("Fold is not idempotent on %q: %q then %q", text, folded, again)
+		}
+		for _, r := range folded {
+			if arabic.IsDiacritic(r) || r == arabic.Tatweel {
+				t.Errorf("Fold(%q) = %q keeps %U", text, folded, r)
+			}
+		}
+		if variants := arabic.NormalizeLetters(folded); variants != folded {
+			t.Errorf("Fold(%q) = %q keeps letter variants", text, folded)
+		}
+		if got := arabic.Fold(decorate(text)); got != folded {
+			t.Errorf("Fold(%q) = %q, want %q", decorate(text), got, folded)
+		}
+
+		stepwise := arabic.NormalizeLetters(arabic.RemoveTatweel(arabic.RemoveDiacritics(arabic.NFC(text))))
+		if folded != stepwise {
+			t.Errorf("Fold(%q) = %q, but the individual steps give %q", text, folded, stepwise)
+		}
+	}
+}
+
+func TestRemoveDiacritics(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"العِلْمُ نُورٌ", "العلم نور"},
+		{"مَنْ جَدَّ وَجَدَ", "من جد وجد"},
+		{"الرَّحْمٰنِ", "الرحمن"},
+		{"صَلَّى اللّٰهُ عَلَيْهِ وَسَلَّمَ ۖ", "صلى الله عليه وسلم "},
+		{"الصبر مفتاح الفرج", "الصبر مفتاح الفرج"},
+		{"Knowledge", "Knowledge"},
+	}
+	for _, tt := range tests {
+		if got := arabic.RemoveDiacritics(tt.in); got != tt.want {
+			t.Errorf("RemoveDiacritics(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRemoveTatweel(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"الصبـــر", "الصبر"},
+		{"العـلـم نـور", "العلم نور"},
+		{"ـ", ""},
+		{"العلم نور", "العلم نور"},
+	}
+	for _, tt := range tests {
+		if got := arabic.RemoveTatweel(tt.in); got != tt.want {
+			t.Errorf("RemoveTatweel(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeLetters(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"أ إ آ ٱ", "ا ا ا ا"},
+		{"يؤدي", "يودي"},
+		{"العزائم", "العزايم"},
+		{"الجنة", "الجنه"},
+		{"على إلى", "علي الي"},
+		{"الصبر", "الصبر"},
+	}
+	for _, tt := range tests {
+		if got := arabic.NormalizeLetters(tt.in); got != tt.want {
+			t.Errorf("NormalizeLetters(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeDigits(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"٠١٢٣٤٥٦٧٨٩", "0123456789"},
+		{"۰۱۲۳۴۵۶۷۸۹", "0123456789"},
+		{"سنة ١٤٤٥ هـ", "سنة 1445 هـ"},
+		{"۱۲ و٣٤ and 56", "12 و34 and 56"},
+		{"بلا أرقام", "بلا أرقام"},
+	}
+	for _, tt := range tests {
+		if got := arabic.NormalizeDigits(tt.in); got != tt.want {
+			t.Errorf("NormalizeDigits(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNFC(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"\u0627\u0654", "\u0623"}, // alef + hamza above
+		{"\u0627\u0655", "\u0625"}, // alef + hamza below
+		{"\u0627\u0653", "\u0622"}, // alef + madda
+		{"\u0648\u0654", "\u0624"}, // waw + hamza above
+		{"\u064A\u0654", "\u0626"}, // yaa + hamza above
+		{"\u0627\u0654\u0647\u0644", "أهل"},
+		{"العلم نور", "العلم نور"},
+	}
+	for _, tt := range tests {
+		if got := arabic.NFC(tt.in); got != tt.want {
+			t.Errorf("NFC(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNFCSamples(t *testing.T) {
+	for _, text := range samples {
+		if got := arabic.NFC(text); got != text {
+			t.Errorf("sample %q is not in NFC: %q", text, got)
+		}
+		decomposed := norm.NFD.String(text)
+		if got := arabic.NFC(decomposed); got != text {
+			t.Errorf("NFC(NFD(%q)) = %q", text, got)
+		}
+		if arabic.Fold(decomposed) != arabic.Fold(text) {
+			t.Errorf("Fold differs between the NFD and NFC forms of %q", text)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package arabic_test
+
+// samples are seed quotes covering hamza seats, alef maqsura, ta marbuta,
+// tanween, the proclitics and the name Allah, used by the tests that check
+// properties over real text
+var samples = []string{
+	"إنما الأعمال بالنيات",
+	"من كان في حاجة أخيه كان الله في حاجته",
+	"خير الناس أنفعهم للناس",
+	"الصمت حكمة وقليل فاعله",
+	"لا تكن عبداً لغيرك وقد جعلك الله حراً",
+	"القلب إذا أقبل على الله أقبل الله عليه",
+	"الدنيا مزرعة الآخرة",
+	"على قدر أهل العزم تأتي العزائم",
+	"ومن يك ذا فم مر مريض يجد مراً به الماء الزلالا",
+	"كلما ازددت علماً ازددت علماً بجهلي",
+	"من أراد السعادة الأبدية فليلزم عتبة العبودية",
+	"الجهل يؤدي إلى الخوف والخوف يؤدي إلى الكراهية",
+	"القلوب آنية الله في أرضه",
+	"الطرق إلى الله بعدد أنفاس الخلائق",
+	"من سلك طريقاً يلتمس فيه علماً سهل الله له طريقاً إلى الجنة",
+	"لا يستغني طالب العلم عن أربعة: ذكاء الطبع وطول الباع وكثرة الاطلاع وطول العمر",
+	"آفة العلماء الوقوف مع المتشابه",
+	"لولا السنتان لهلك النعمان",
+	"بني آدم أعضاء جسد واحد",
+	"درهم وقاية خير من قنطار علاج",
+}
@@ -0,0 +1,128 @@
+package arabic
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Proclitics that Tokenize splits from the front of a word
+const (
+	Waw     = "و"  // conjunction "and"
+	Fa      = "ف"  // conjunction "so, then"
+	Ba      = "ب"  // preposition "with, by"
+	Lam     = "ل"  // preposition "for, to"
+	Article = "ال" // definite article
+)
+
+// Allah keeps its article: splitting it would leave a meaningless "له".
+// After ل its alef is elided, giving لله.
+const (
+	allah    = "الله"
+	lamAllah = "لله"
+)
+
+// allahSuffixes are the endings that may follow Allah without forming
+// another word, as in اللهم; words like اللهو only share its letters
+var allahSuffixes = map[string]bool{"": true, "م": true}
+
+// isAllah reports whether word is form (الله or لله) followed by nothing or
+// one of allahSuffixes
+func isAllah(word, form string) bool {
+	return strings.HasPrefix(word, form) && allahSuffixes[word[len(form):]]
+}
+
+// particles are the short function words a conjunction is split from when
+// no article follows, as in ومن، فلا، وقد
+var particles = map[string]bool{
+	"من": true, "ما": true, "لا": true, "لم": true, "لن": true, "قد": true,
+	"في": true, "هو": true, "هي": true, "هم": true, "ان": true, "لو": true,
+}
+
+// IsProclitic reports whether tok is one of the proclitics Tokenize emits
+func IsProclitic(tok string) bool {
+	switch tok {
+	case Waw, Fa, Ba, Lam, Article:
+		return true
+	}
+	return false
+}
+
+// Tokenize folds s and splits it into words, emitting the conjunctions و and
+// ف, the prepositions ب and ل and the article ال as separate tokens ahead of
+// the word they are attached to: "وبالعلم" yields و، ب، ال، علم. Digits are
+// converted to ASCII.
+//
+// Splitting is conservative so that root letters are not mistaken for
+// proclitics: a conjunction is only split in front of the article, a
+// preposition plus article or a common particle such as من or لا; ب and ل
+// only in front of the article (including the contracted "لل"); and the
+// article only when at least two letters remain. Non-Arabic words
+// are returned folded but otherwise untouched.
+func Tokenize(s string) []string {
+	words := strings.FieldsFunc(NormalizeDigits(Fold(s)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+
+	tokens := make([]string, 0, len(words))
+	for _, word := range words {
+		tokens = appendWord(tokens, word)
+	}
+	return tokens
+}
+
+// appendWord appends the proclitics and stem of a single folded word
+func appendWord(tokens []string, word string) []string {
+	if !ContainsArabic(word) {
+		return append(tokens, word)
+	}
+
+	if (strings.HasPrefix(word, Waw) || strings.HasPrefix(word, Fa)) && splitsConjunction(word[len(Waw):]) {
+		tokens = append(tokens, word[:len(Waw)])
+		word = word[len(Waw):]
+	}
+
+	switch {
+	case strings.HasPrefix(word, lamAllah):
+		// ل before the article and a word starting with ل drops the alef and
+		// one ل: لله is ل + الله and للهو is ل + اللهو
+		tokens = append(tokens, Lam)
+		word = allah + word[len(lamAllah):]
+	case strings.HasPrefix(word, Lam+Lam) && runeCount(word) >= 4:
+		// the article's alef is elided after ل: للناس is ل + الناس
+		tokens = append(tokens, Lam)
+		word = "ا" + word[len(Lam):]
+	case hasPrefix(word, Ba+Article, 2) || hasPrefix(word, Lam+Article, 2):
+		tokens = append(tokens, word[:len(Ba)])
+		word = word[len(Ba):]
+	}
+
+	if isAllah(word, allah) {
+		return append(tokens, word)
+	}
+	if hasPrefix(word, Article, 2) {
+		tokens = append(tokens, Article)
+		word = word[len(Article):]
+	}
+
+	return append(tokens, word)
+}
+
+// splitsConjunction reports whether rest, the word after a leading و or ف,
+// shows that letter to be a conjunction rather than part of the root
+func splitsConjunction(rest string) bool {
+	return particles[rest] ||
+		isAllah(rest, lamAllah) ||
+		hasPrefix(rest, Article, 2) ||
+		hasPrefix(rest, Ba+Article, 2) ||
+		(strings.HasPrefix(rest, Lam+Lam) && runeCount(rest) >= 4)
+}
+
+// hasPrefix reports whether word starts with prefix and keeps at least min
+// letters after it
+func hasPrefix(word, prefix string, min int) bool {
+	return strings.HasPrefix(word, prefix) && runeCount(word[len(prefix):]) >= min
+}
+
+func runeCount(s string) int {
+	return len([]rune(s))
+}
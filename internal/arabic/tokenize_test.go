@@ -0,0 +1,109 @@
+package arabic_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/albantanie/mahfudzot-generator/internal/arabic"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		// proclitics
+		{"وبالعلم", []string{"و", "ب", "ال", "علم"}},
+		{"إنما الأعمال بالنيات", []string{"انما", "ال", "اعمال", "ب", "ال", "نيات"}},
+		{"خير الناس أنفعهم للناس", []string{"خير", "ال", "ناس", "انفعهم", "ل", "ال", "ناس"}},
+		{"والنار", []string{"و", "ال", "نار"}},
+		{"ومن", []string{"و", "من"}},
+		{"فقد", []string{"ف", "قد"}},
+		{"فهو", []string{"ف", "هو"}},
+
+		// Allah keeps its article, but words merely starting with its letters do not
+		{"الله", []string{"الله"}},
+		{"اللهم", []string{"اللهم"}},
+		{"لله", []string{"ل", "الله"}},
+		{"ولله", []string{"و", "ل", "الله"}},
+		{"والله", []string{"و", "الله"}},
+		{"للهو", []string{"ل", "ال", "لهو"}},
+		{"باللهو", []string{"ب", "ال", "لهو"}},
+		{"واللهو", []string{"و", "ال", "لهو"}},
+
+		// root letters that look like proclitics stay attached
+		{"الصمت حكمة وقليل فاعله", []string{"ال", "صمت", "حكمه", "وقليل", "فاعله"}},
+		{"بني آدم أعضاء جسد واحد", []string{"بني", "ادم", "اعضاء", "جسد", "واحد"}},
+		{"درهم وقاية خير من قنطار علاج", []string{"درهم", "وقايه", "خير", "من", "قنطار", "علاج"}},
+		{"لغيرك بجهلي فليلزم", []string{"لغيرك", "بجهلي", "فليلزم"}},
+		{"الي", []string{"الي"}},
+		{"لا", []string{"لا"}},
+
+		// folding, digits and non-Arabic words
+		{"العِلْمُ نُـورٌ", []string{"ال", "علم", "نور"}},
+		{"بعد ٣ أيام و۱۲ ساعة", []string{"بعد", "3", "ايام", "و12", "ساعه"}},
+		{"Knowledge is Light", []string{"knowledge", "is", "light"}},
+		{"أربعة: ذكاء الطبع", []string{"اربعه", "ذكاء", "ال", "طبع"}},
+		{"", []string{}},
+	}
+	for _, tt := range tests {
+		if got := arabic.Tokenize(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestTokenizeSamples checks that every word of the samples yields exactly
+// one stem, preceded only by proclitics, and that the stem is a suffix of the
+// folded word or of the word with its elided article restored
+func TestTokenizeSamples(t *testing.T) {
+	for _, text := range samples {
+		for _, word := range strings.Fields(text) {
+			tokens := arabic.Tokenize(word)
+			if len(tokens) == 0 {
+				t.Errorf("Tokenize(%q) in %q returned no tokens", word, text)
+				continue
+			}
+
+			stem := tokens[len(tokens)-1]
+			for _, tok := range tokens[:len(tokens)-1] {
+				if !arabic.IsProclitic(tok) {
+					t.Errorf("Tokenize(%q) in %q = %q: %q is not a proclitic", word, text, tokens, tok)
+				}
+			}
+			if len(tokens) > 1 && utf8.RuneCountInString(stem) < 2 {
+				t.Errorf("Tokenize(%q) in %q = %q leaves a stem shorter than two letters", word, text, tokens)
+			}
+
+			whole := arabic.Fold(strings.Trim(word, ":.,،؛"))
+			if !strings.HasSuffix(whole, stem) && !strings.HasSuffix("ا"+whole, stem) {
+				t.Errorf("Tokenize(%q) in %q = %q: stem %q is not part of %q", word, text, tokens, stem, whole)
+			}
+		}
+	}
+}
+
+func TestIsProclitic(t *testing.T) {
+	tests := []struct {
+		tok  string
+		want bool
+	}{
+		{"و", true},
+		{"ف", true},
+		{"ب", true},
+		{"ل", true},
+		{"ال", true},
+		{"الله", false},
+		{"من", false},
+		{"لا", false},
+		{"ك", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := arabic.IsProclitic(tt.tok); got != tt.want {
+			t.Errorf("IsProclitic(%q) = %v, want %v", tt.tok, got, tt.want)
+		}
+	}
+}
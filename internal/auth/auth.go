@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/albantanie/mahfudzot-generator/internal/models"
+)
+
+// Role is the access level granted to an API key
+type Role string
+
+// Supported roles, from least to most privileged
+const (
+	RoleReader Role = "reader"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+// keyPrefix marks strings minted by GenerateKey
+const keyPrefix = "mfz_"
+
+// prefixLength is the number of leading key characters kept in clear for identification
+const prefixLength = 12
+
+var roleLevels = map[Role]int{
+	RoleReader: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+// ParseRole converts a string to a Role, rejecting unknown values
+func ParseRole(s string) (Role, error) {
+	role := Role(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := roleLevels[role]; !ok {
+		return "", fmt.Errorf("unknown role %q (expected reader, editor or admin)", s)
+	}
+	return role, nil
+}
+
+// Allows reports whether r grants at least the access of required
+func (r Role) Allows(required Role) bool {
+	return roleLevels[r] > 0 && roleLevels[r] >= roleLevels[required]
+}
+
+// GenerateKey returns a new random API key in clear text
+func GenerateKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate key: %w", err)
+	}
+	return keyPrefix + hex.EncodeToString(buf), nil
+}
+
+// HashKey returns the value stored for key; the clear text is never persisted
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// KeyPrefix returns the leading part of key that is safe to display
+func KeyPrefix(key string) string {
+	if len(key) <= prefixLength {
+		return key
+	}
+	return key[:prefixLength]
+}
+
+type contextKey struct{}
+
+// WithKey returns a copy of ctx carrying the authenticated API key
+func WithKey(ctx context.Context, key *models.APIKey) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+// KeyFromContext returns the authenticated API key, or nil for anonymous requests
+func KeyFromContext(ctx context.Context) *models.APIKey {
+	key, _ := ctx.Value(contextKey{}).(*models.APIKey)
+	return key
+}
@@ -3,12 +3,16 @@ package config
 import (
 	"os"
 	"strconv"
+	"time"
 )
 
 // Config holds all configuration for the application
 type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
+	Auth     AuthConfig
+	Daily    DailyConfig
+	Shuffle  ShuffleConfig
 }
 
 // ServerConfig holds server configuration
@@ -25,6 +29,33 @@ type DatabaseConfig struct {
 	Password string
 	DBName   string
 	SSLMode  string
+	// QueryTimeout bounds every repository query; zero disables the limit
+	QueryTimeout time.Duration
+}
+
+// AuthConfig holds API authentication configuration
+type AuthConfig struct {
+	// PublicRead lets requests without an API key use reader endpoints
+	PublicRead bool
+	// BootstrapKey, when set, is registered as an admin key at startup
+	BootstrapKey string
+}
+
+// DailyConfig holds quote of the day configuration
+type DailyConfig struct {
+	// HijriOffset shifts computed Hijri dates by this many days to follow
+	// local moon sighting
+	HijriOffset int
+}
+
+// ShuffleConfig holds configuration of the no-repeat random mode
+type ShuffleConfig struct {
+	// MaxClients bounds how many clients' state is kept in memory; values
+	// below one fall back to the default
+	MaxClients int
+	// TTL is how long a client's state survives without requests; values
+	// that are not positive fall back to the default
+	TTL time.Duration
 }
 
 // Load loads configuration from environment variables
@@ -35,12 +66,24 @@ func Load() *Config {
 			Host: getEnv("HOST", "localhost"),
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnvAsInt("DB_PORT", 5432),
-			User:     getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", ""),
-			DBName:   getEnv("DB_NAME", "mahfudzot"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			Host:         getEnv("DB_HOST", "localhost"),
+			Port:         getEnvAsInt("DB_PORT", 5432),
+			User:         getEnv("DB_USER", "postgres"),
+			Password:     getEnv("DB_PASSWORD", ""),
+			DBName:       getEnv("DB_NAME", "mahfudzot"),
+			SSLMode:      getEnv("DB_SSLMODE", "disable"),
+			QueryTimeout: getEnvAsDuration("DB_QUERY_TIMEOUT", 5*time.Second),
+		},
+		Auth: AuthConfig{
+			PublicRead:   getEnvAsBool("AUTH_PUBLIC_READ", true),
+			BootstrapKey: getEnv("AUTH_BOOTSTRAP_KEY", ""),
+		},
+		Daily: DailyConfig{
+			HijriOffset: getEnvAsInt("HIJRI_OFFSET", 0),
+		},
+		Shuffle: ShuffleConfig{
+			MaxClients: getEnvAsPositiveInt("SHUFFLE_MAX_CLIENTS", 10000),
+			TTL:        getEnvAsPositiveDuration("SHUFFLE_TTL", 24*time.Hour),
 		},
 	}
 }
@@ -62,3 +105,41 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// getEnvAsPositiveInt gets an environment variable as a positive integer or
+// returns a default value
+func getEnvAsPositiveInt(key string, defaultValue int) int {
+	if intValue := getEnvAsInt(key, defaultValue); intValue > 0 {
+		return intValue
+	}
+	return defaultValue
+}
+
+// getEnvAsBool gets an environment variable as boolean or returns a default value
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsDuration gets an environment variable as duration (e.g. "5s") or returns a default value
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if durationValue, err := time.ParseDuration(value); err == nil {
+			return durationValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsPositiveDuration gets an environment variable as a positive duration
+// or returns a default value
+func getEnvAsPositiveDuration(key string, defaultValue time.Duration) time.Duration {
+	if durationValue := getEnvAsDuration(key, defaultValue); durationValue > 0 {
+		return durationValue
+	}
+	return defaultValue
+}
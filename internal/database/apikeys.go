@@ -0,0 +1,145 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/albantanie/mahfudzot-generator/internal/models"
+)
+
+// APIKeyRepository defines the interface for API key operations
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, name, role, keyHash, prefix string) (*models.APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]*models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int) error
+}
+
+// Store combines every repository the API server depends on
+type Store interface {
+	QuoteRepository
+	APIKeyRepository
+	OccasionRepository
+	ValueRepository
+	AuthorRepository
+	CategoryRepository
+}
+
+// CreateAPIKey stores a new API key
+func (db *DB) CreateAPIKey(ctx context.Context, name, role, keyHash, prefix string) (*models.APIKey, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	query := `
+		INSERT INTO api_keys (name, role, key_hash, prefix)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, name, prefix, key_hash, role, created_at, revoked_at
+	`
+
+	key := &models.APIKey{}
+	err := db.QueryRowContext(ctx, query, name, role, keyHash, prefix).Scan(
+		&key.ID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&key.Role,
+		&key.CreatedAt,
+		&key.RevokedAt,
+	)
+
+	if err != nil {
+		return nil, translateError(err, "api key")
+	}
+
+	return key, nil
+}
+
+// GetAPIKeyByHash retrieves an API key by the hash of its clear text
+func (db *DB) GetAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT id, name, prefix, key_hash, role, created_at, revoked_at
+		FROM api_keys
+		WHERE key_hash = $1
+	`
+
+	key := &models.APIKey{}
+	err := db.QueryRowContext(ctx, query, keyHash).Scan(
+		&key.ID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&key.Role,
+		&key.CreatedAt,
+		&key.RevokedAt,
+	)
+
+	if err != nil {
+		return nil, translateError(err, "api key")
+	}
+
+	return key, nil
+}
+
+// ListAPIKeys retrieves all API keys, including revoked ones
+func (db *DB) ListAPIKeys(ctx context.Context) ([]*models.APIKey, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT id, name, prefix, key_hash, role, created_at, revoked_at
+		FROM api_keys
+		ORDER BY id
+	`
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, translateError(err, "api keys")
+	}
+	defer rows.Close()
+
+	var keys []*models.APIKey
+	for rows.Next() {
+		key := &models.APIKey{}
+		err := rows.Scan(
+			&key.ID,
+			&key.Name,
+			&key.Prefix,
+			&key.KeyHash,
+			&key.Role,
+			&key.CreatedAt,
+			&key.RevokedAt,
+		)
+		if err != nil {
+			return nil, translateError(err, "api keys")
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, translateError(rows.Err(), "api keys")
+}
+
+// RevokeAPIKey marks an API key as revoked
+func (db *DB) RevokeAPIKey(ctx context.Context, id int) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP WHERE id = $1 AND revoked_at IS NULL"
+	result, err := db.ExecContext(ctx, query, id)
+	if err != nil {
+		return translateError(err, fmt.Sprintf("api key with id %d", id))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return translateError(err, fmt.Sprintf("api key with id %d", id))
+	}
+
+	if rowsAffected == 0 {
+		return newError(ErrNotFound, "active api key with id %d not found", id)
+	}
+
+	return nil
+}
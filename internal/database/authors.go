@@ -0,0 +1,344 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/albantanie/mahfudzot-generator/internal/models"
+	"github.com/lib/pq"
+)
+
+// AuthorRepository defines the interface for author operations. Authors are
+// created implicitly when a quote names an author that does not exist yet;
+// a quote naming an alias is attributed to the author owning the alias.
+type AuthorRepository interface {
+	ListAuthors(ctx context.Context) ([]*models.Author, error)
+	GetAuthor(ctx context.Context, id int) (*models.Author, error)
+	ResolveAuthor(ctx context.Context, name string) (*models.Author, error)
+	UpdateAuthor(ctx context.Context, id int, req *models.AuthorRequest) (*models.Author, error)
+	MergeAuthors(ctx context.Context, sourceID, targetID int) (*models.Author, error)
+}
+
+// authorColumns lists the columns read by scanAuthor, in order
+const authorColumns = `a.id, a.name, COALESCE(a.name_arabic, ''),
+		ARRAY(SELECT al.alias FROM author_aliases al WHERE al.author_id = a.id ORDER BY al.alias),
+		COALESCE(a.kunya, ''),
+		a.birth_year_hijri, a.death_year_hijri, a.birth_year, a.death_year,
+		COALESCE(a.bio, ''), COALESCE(a.era, ''),
+		(SELECT COUNT(*) FROM quotes q WHERE q.author_id = a.id), a.created_at, a.updated_at`
+
+// scanAuthor reads a row selected with authorColumns
+func scanAuthor(row rowScanner) (*models.Author, error) {
+	author := &models.Author{}
+	var birthHijri, deathHijri, birth, death sql.NullInt32
+	err := row.Scan(
+		&author.ID,
+		&author.Name,
+		&author.NameArabic,
+		pq.Array(&author.Aliases),
+		&author.Kunya,
+		&birthHijri,
+		&deathHijri,
+		&birth,
+		&death,
+		&author.Bio,
+		&author.Era,
+		&author.QuoteCount,
+		&author.CreatedAt,
+		&author.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	author.BirthYearHijri = nullableInt(birthHijri)
+	author.DeathYearHijri = nullableInt(deathHijri)
+	author.BirthYear = nullableInt(birth)
+	author.DeathYear = nullableInt(death)
+	if author.Aliases == nil {
+		author.Aliases = []string{}
+	}
+	return author, nil
+}
+
+// nullableInt converts a nullable column to an optional int
+func nullableInt(n sql.NullInt32) *int {
+	if !n.Valid {
+		return nil
+	}
+	v := int(n.Int32)
+	return &v
+}
+
+// sortAuthors orders authors by number of quotes, most first, then by name
+func sortAuthors(authors []*models.Author) {
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].QuoteCount != authors[j].QuoteCount {
+			return authors[i].QuoteCount > authors[j].QuoteCount
+		}
+		return authors[i].Name < authors[j].Name
+	})
+}
+
+// ListAuthors returns every author with its number of quotes, the authors
+// with most quotes first
+func (db *DB) ListAuthors(ctx context.Context) ([]*models.Author, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, "SELECT "+authorColumns+" FROM authors a")
+	if err != nil {
+		return nil, translateError(err, "authors")
+	}
+	defer rows.Close()
+
+	authors := []*models.Author{}
+	for rows.Next() {
+		author, err := scanAuthor(rows)
+		if err != nil {
+			return nil, translateError(err, "authors")
+		}
+		authors = append(authors, author)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err, "authors")
+	}
+
+	sortAuthors(authors)
+	return authors, nil
+}
+
+// GetAuthor retrieves an author by ID
+func (db *DB) GetAuthor(ctx context.Context, id int) (*models.Author, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	author, err := scanAuthor(db.QueryRowContext(ctx, "SELECT "+authorColumns+" FROM authors a WHERE a.id = $1", id))
+	if err != nil {
+		return nil, translateError(err, fmt.Sprintf("author with id %d", id))
+	}
+
+	return author, nil
+}
+
+// ResolveAuthor retrieves the author whose name or one of whose aliases is
+// name, ignoring case
+func (db *DB) ResolveAuthor(ctx context.Context, name string) (*models.Author, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT " + authorColumns + ` FROM authors a
+		WHERE LOWER(a.name) = LOWER($1)
+			OR a.id = (SELECT al.author_id FROM author_aliases al WHERE LOWER(al.alias) = LOWER($1))
+		ORDER BY a.name = $1 DESC
+		LIMIT 1`
+
+	author, err := scanAuthor(db.QueryRowContext(ctx, query, name))
+	if err != nil {
+		return nil, translateError(err, fmt.Sprintf("author %q", name))
+	}
+
+	return author, nil
+}
+
+// UpdateAuthor replaces the details of an author. Renaming an author renames
+// it on all of its quotes in the same transaction.
+func (db *DB) UpdateAuthor(ctx context.Context, id int, req *models.AuthorRequest) (*models.Author, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, translateError(err, "author")
+	}
+	defer tx.Rollback()
+
+	// A name used as another author's alias would make quotes ambiguous
+	var taken bool
+	err = tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM author_aliases WHERE LOWER(alias) = LOWER($1) AND author_id <> $2)", req.Name, id).Scan(&taken)
+	if err != nil {
+		return nil, translateError(err, "author aliases")
+	}
+	if taken {
+		return nil, newError(ErrConflict, "author %q already exists", req.Name)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE authors
+		SET name = $2, name_arabic = NULLIF($3, ''), kunya = NULLIF($4, ''),
+			birth_year_hijri = $5, death_year_hijri = $6, birth_year = $7, death_year = $8,
+			bio = NULLIF($9, ''), era = NULLIF($10, '')
+		WHERE id = $1`,
+		id,
+		req.Name,
+		req.NameArabic,
+		req.Kunya,
+		req.BirthYearHijri,
+		req.DeathYearHijri,
+		req.BirthYear,
+		req.DeathYear,
+		req.Bio,
+		req.Era,
+	)
+	if err != nil {
+		return nil, translateError(err, fmt.Sprintf("author with id %d", id))
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return nil, translateError(err, fmt.Sprintf("author with id %d", id))
+	} else if rowsAffected == 0 {
+		return nil, newError(ErrNotFound, "author with id %d not found", id)
+	}
+
+	if err := replaceAliases(ctx, tx, id, req.Name, req.Aliases); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE quotes SET author = $2 WHERE author_id = $1 AND author <> $2", id, req.Name)
+	if err != nil {
+		return nil, translateError(err, "quotes")
+	}
+
+	author, err := scanAuthor(tx.QueryRowContext(ctx, "SELECT "+authorColumns+" FROM authors a WHERE a.id = $1", id))
+	if err != nil {
+		return nil, translateError(err, fmt.Sprintf("author with id %d", id))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, translateError(err, "author")
+	}
+
+	db.corpus.invalidate()
+	return author, nil
+}
+
+// replaceAliases sets the aliases of the author id named name. Aliases equal
+// to name are skipped; an alias that is another author's name or alias is a
+// conflict.
+func replaceAliases(ctx context.Context, tx *sql.Tx, id int, name string, aliases []string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM author_aliases WHERE author_id = $1", id); err != nil {
+		return translateError(err, "author aliases")
+	}
+
+	for _, alias := range aliases {
+		if strings.EqualFold(alias, name) {
+			continue
+		}
+
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO author_aliases (author_id, alias)
+			SELECT $1, $2
+			WHERE NOT EXISTS (SELECT 1 FROM authors WHERE LOWER(name) = LOWER($2) AND id <> $1)
+			ON CONFLICT DO NOTHING`, id, alias)
+		if err != nil {
+			return translateError(err, fmt.Sprintf("alias %q", alias))
+		}
+		if rowsAffected, err := result.RowsAffected(); err != nil {
+			return translateError(err, fmt.Sprintf("alias %q", alias))
+		} else if rowsAffected == 0 {
+			var owner int
+			err := tx.QueryRowContext(ctx, "SELECT author_id FROM author_aliases WHERE LOWER(alias) = LOWER($1)", alias).Scan(&owner)
+			if err == sql.ErrNoRows || (err == nil && owner != id) {
+				return newError(ErrConflict, "alias %q already belongs to another author", alias)
+			} else if err != nil {
+				return translateError(err, fmt.Sprintf("alias %q", alias))
+			}
+		}
+	}
+
+	return nil
+}
+
+// MergeAuthors moves all quotes and aliases of the author sourceID to the
+// author targetID and deletes the source, recording its name as an alias of
+// the target. Details the target lacks are taken from the source. Everything
+// happens in one transaction.
+func (db *DB) MergeAuthors(ctx context.Context, sourceID, targetID int) (*models.Author, error) {
+	if sourceID == targetID {
+		return nil, newError(ErrValidation, "cannot merge author %d into itself", sourceID)
+	}
+
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, translateError(err, "author")
+	}
+	defer tx.Rollback()
+
+	// Lock both authors in id order so concurrent merges cannot deadlock
+	names := make(map[int]string, 2)
+	rows, err := tx.QueryContext(ctx, "SELECT id, name FROM authors WHERE id IN ($1, $2) ORDER BY id FOR UPDATE", sourceID, targetID)
+	if err != nil {
+		return nil, translateError(err, "author")
+	}
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			rows.Close()
+			return nil, translateError(err, "author")
+		}
+		names[id] = name
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err, "author")
+	}
+	for _, id := range []int{sourceID, targetID} {
+		if _, ok := names[id]; !ok {
+			return nil, newError(ErrNotFound, "author with id %d not found", id)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE authors t
+		SET name_arabic = COALESCE(t.name_arabic, s.name_arabic),
+			kunya = COALESCE(t.kunya, s.kunya),
+			birth_year_hijri = COALESCE(t.birth_year_hijri, s.birth_year_hijri),
+			death_year_hijri = COALESCE(t.death_year_hijri, s.death_year_hijri),
+			birth_year = COALESCE(t.birth_year, s.birth_year),
+			death_year = COALESCE(t.death_year, s.death_year),
+			bio = COALESCE(t.bio, s.bio),
+			era = COALESCE(t.era, s.era)
+		FROM authors s
+		WHERE t.id = $2 AND s.id = $1`, sourceID, targetID)
+	if err != nil {
+		return nil, translateError(err, "author")
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE author_aliases SET author_id = $2 WHERE author_id = $1", sourceID, targetID)
+	if err != nil {
+		return nil, translateError(err, "author aliases")
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE quotes SET author = $2 WHERE author_id = $1", sourceID, names[targetID])
+	if err != nil {
+		return nil, translateError(err, "quotes")
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM authors WHERE id = $1", sourceID)
+	if err != nil {
+		return nil, translateError(err, "author")
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO author_aliases (author_id, alias) VALUES ($1, $2) ON CONFLICT DO NOTHING", targetID, names[sourceID])
+	if err != nil {
+		return nil, translateError(err, "author aliases")
+	}
+
+	author, err := scanAuthor(tx.QueryRowContext(ctx, "SELECT "+authorColumns+" FROM authors a WHERE a.id = $1", targetID))
+	if err != nil {
+		return nil, translateError(err, fmt.Sprintf("author with id %d", targetID))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, translateError(err, "author")
+	}
+
+	db.corpus.invalidate()
+	return author, nil
+}
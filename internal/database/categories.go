@@ -0,0 +1,195 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+
+	"github.com/albantanie/mahfudzot-generator/internal/models"
+)
+
+// CategoryRepository defines the interface for the category taxonomy
+type CategoryRepository interface {
+	ListCategories(ctx context.Context) ([]*models.Category, error)
+	ResolveCategory(ctx context.Context, ref string) (*models.Category, error)
+}
+
+// DefaultCategories returns the default category taxonomy, parents before
+// their children. It places every category used by GetSeedData in the tree.
+func DefaultCategories() []*models.CategoryDefinition {
+	return []*models.CategoryDefinition{
+		{Slug: "knowledge", Name: "Knowledge", NameArabic: "العلم", NameIndonesian: "Ilmu"},
+		{Slug: "learning", Name: "Learning", NameArabic: "التعلم", NameIndonesian: "Belajar", Parent: "knowledge"},
+		{Slug: "scholarship", Name: "Scholarship", NameArabic: "العلماء", NameIndonesian: "Keulamaan", Parent: "knowledge"},
+		{Slug: "reason", Name: "Reason", NameArabic: "العقل", NameIndonesian: "Akal", Parent: "knowledge"},
+		{Slug: "philosophy", Name: "Philosophy", NameArabic: "الفلسفة", NameIndonesian: "Filsafat", Parent: "knowledge"},
+		{Slug: "self-knowledge", Name: "Self-Knowledge", NameArabic: "معرفة النفس", NameIndonesian: "Mengenal Diri", Parent: "knowledge"},
+		{Slug: "history", Name: "History", NameArabic: "التاريخ", NameIndonesian: "Sejarah", Parent: "knowledge"},
+		{Slug: "universe", Name: "Universe", NameArabic: "الكون", NameIndonesian: "Alam Semesta", Parent: "knowledge"},
+
+		{Slug: "akhlaq", Name: "Akhlaq", NameArabic: "الأخلاق", NameIndonesian: "Akhlak"},
+		{Slug: "character", Name: "Character", NameArabic: "الخلق", NameIndonesian: "Budi Pekerti", Parent: "akhlaq"},
+		{Slug: "ethics", Name: "Ethics", NameArabic: "الآداب", NameIndonesian: "Etika", Parent: "akhlaq"},
+		{Slug: "humility", Name: "Humility", NameArabic: "التواضع", NameIndonesian: "Rendah Hati", Parent: "akhlaq"},
+		{Slug: "patience", Name: "Patience", NameArabic: "الصبر", NameIndonesian: "Sabar", Parent: "akhlaq"},
+		{Slug: "intention", Name: "Intention", NameArabic: "النية", NameIndonesian: "Niat", Parent: "akhlaq"},
+		{Slug: "truth", Name: "Truth", NameArabic: "الصدق", NameIndonesian: "Kejujuran", Parent: "akhlaq"},
+		{Slug: "justice", Name: "Justice", NameArabic: "العدل", NameIndonesian: "Keadilan", Parent: "akhlaq"},
+		{Slug: "effort", Name: "Effort", NameArabic: "الجد", NameIndonesian: "Kesungguhan", Parent: "akhlaq"},
+		{Slug: "determination", Name: "Determination", NameArabic: "العزيمة", NameIndonesian: "Tekad", Parent: "effort"},
+		{Slug: "excellence", Name: "Excellence", NameArabic: "الإتقان", NameIndonesian: "Keunggulan", Parent: "effort"},
+
+		{Slug: "ibadah", Name: "Ibadah", NameArabic: "العبادة", NameIndonesian: "Ibadah"},
+		{Slug: "faith", Name: "Faith", NameArabic: "الإيمان", NameIndonesian: "Iman", Parent: "ibadah"},
+		{Slug: "prayer", Name: "Prayer", NameArabic: "الصلاة", NameIndonesian: "Shalat", Parent: "ibadah"},
+		{Slug: "quran", Name: "Quran", NameArabic: "القرآن", NameIndonesian: "Al-Qur'an", Parent: "ibadah"},
+		{Slug: "spirituality", Name: "Spirituality", NameArabic: "الروحانية", NameIndonesian: "Spiritualitas", Parent: "ibadah"},
+		{Slug: "jurisprudence", Name: "Jurisprudence", NameArabic: "الفقه", NameIndonesian: "Fikih", Parent: "ibadah"},
+
+		{Slug: "society", Name: "Society", NameArabic: "المجتمع", NameIndonesian: "Masyarakat"},
+		{Slug: "brotherhood", Name: "Brotherhood", NameArabic: "الأخوة", NameIndonesian: "Persaudaraan", Parent: "society"},
+		{Slug: "friendship", Name: "Friendship", NameArabic: "الصداقة", NameIndonesian: "Persahabatan", Parent: "brotherhood"},
+		{Slug: "love", Name: "Love", NameArabic: "الحب", NameIndonesian: "Cinta", Parent: "society"},
+		{Slug: "humanity", Name: "Humanity", NameArabic: "الإنسانية", NameIndonesian: "Kemanusiaan", Parent: "society"},
+		{Slug: "service", Name: "Service", NameArabic: "خدمة الناس", NameIndonesian: "Pengabdian", Parent: "society"},
+		{Slug: "freedom", Name: "Freedom", NameArabic: "الحرية", NameIndonesian: "Kebebasan", Parent: "society"},
+		{Slug: "reform", Name: "Reform", NameArabic: "الإصلاح", NameIndonesian: "Perbaikan", Parent: "society"},
+		{Slug: "progress", Name: "Progress", NameArabic: "التقدم", NameIndonesian: "Kemajuan", Parent: "society"},
+
+		{Slug: "life", Name: "Life", NameArabic: "الحياة", NameIndonesian: "Kehidupan"},
+		{Slug: "wisdom", Name: "Wisdom", NameArabic: "الحكمة", NameIndonesian: "Hikmah", Parent: "life"},
+		{Slug: "perspective", Name: "Perspective", NameArabic: "النظرة", NameIndonesian: "Sudut Pandang", Parent: "life"},
+		{Slug: "happiness", Name: "Happiness", NameArabic: "السعادة", NameIndonesian: "Kebahagiaan", Parent: "life"},
+		{Slug: "time", Name: "Time", NameArabic: "الوقت", NameIndonesian: "Waktu", Parent: "life"},
+		{Slug: "travel", Name: "Travel", NameArabic: "السفر", NameIndonesian: "Perjalanan", Parent: "life"},
+		{Slug: "health", Name: "Health", NameArabic: "الصحة", NameIndonesian: "Kesehatan", Parent: "life"},
+		{Slug: "prevention", Name: "Prevention", NameArabic: "الوقاية", NameIndonesian: "Pencegahan", Parent: "health"},
+	}
+}
+
+// buildCategoryTree links categories to their parents, ordering siblings by
+// name, and returns the roots
+func buildCategoryTree(categories []*models.Category) []*models.Category {
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Name < categories[j].Name
+	})
+
+	byID := make(map[int]*models.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	roots := []*models.Category{}
+	for _, category := range categories {
+		if category.ParentID == nil || byID[*category.ParentID] == nil {
+			roots = append(roots, category)
+			continue
+		}
+		parent := byID[*category.ParentID]
+		parent.Children = append(parent.Children, category)
+	}
+	return roots
+}
+
+// findCategory returns the category in the trees rooted at roots whose slug
+// or name is ref, ignoring case, or nil
+func findCategory(roots []*models.Category, ref string) *models.Category {
+	for _, category := range roots {
+		if strings.EqualFold(category.Slug, ref) || strings.EqualFold(category.Name, ref) {
+			return category
+		}
+		if found := findCategory(category.Children, ref); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// canonicalCategory returns the spelling of category used by the taxonomy
+// or, failing that, by an existing tag, ignoring case, so that a quote filed
+// under "knowledge" is stored as "Knowledge" like its tag. Unknown categories
+// are kept as given.
+func canonicalCategory(ctx context.Context, tx *sql.Tx, category string) (string, error) {
+	if category == "" {
+		return category, nil
+	}
+
+	query := `
+		SELECT name FROM (
+			SELECT name, 0 AS rank FROM categories WHERE LOWER(name) = LOWER($1)
+			UNION ALL
+			SELECT name, 1 FROM tags WHERE LOWER(name) = LOWER($1)
+		) spellings
+		ORDER BY rank
+		LIMIT 1`
+
+	var name string
+	err := tx.QueryRowContext(ctx, query, category).Scan(&name)
+	if err == sql.ErrNoRows {
+		return category, nil
+	}
+	if err != nil {
+		return "", translateError(err, "category")
+	}
+	return name, nil
+}
+
+// ListCategories returns the category taxonomy as a list of root categories
+// with their descendants. QuoteCount counts the quotes of each category
+// itself, not of its descendants.
+func (db *DB) ListCategories(ctx context.Context) ([]*models.Category, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT c.id, c.slug, c.name, c.name_arabic, c.name_indonesian, c.parent_id,
+			(SELECT COUNT(*) FROM quotes q WHERE LOWER(q.category) = LOWER(c.name)),
+			c.created_at
+		FROM categories c
+	`
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, translateError(err, "categories")
+	}
+	defer rows.Close()
+
+	categories := []*models.Category{}
+	for rows.Next() {
+		category := &models.Category{}
+		err := rows.Scan(
+			&category.ID,
+			&category.Slug,
+			&category.Name,
+			&category.NameArabic,
+			&category.NameIndonesian,
+			&category.ParentID,
+			&category.QuoteCount,
+			&category.CreatedAt,
+		)
+		if err != nil {
+			return nil, translateError(err, "categories")
+		}
+		categories = append(categories, category)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err, "categories")
+	}
+
+	return buildCategoryTree(categories), nil
+}
+
+// ResolveCategory retrieves the category whose slug or name is ref, ignoring
+// case, with its descendants
+func (db *DB) ResolveCategory(ctx context.Context, ref string) (*models.Category, error) {
+	roots, err := db.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if category := findCategory(roots, ref); category != nil {
+		return category, nil
+	}
+	return nil, newError(ErrNotFound, "category %q not found", ref)
+}
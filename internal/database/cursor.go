@@ -0,0 +1,58 @@
+package database
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/albantanie/mahfudzot-generator/internal/models"
+)
+
+// Cursor is a position in a quote listing ordered by (created_at, id).
+// Clients only ever see it in its opaque encoded form.
+type Cursor struct {
+	Sort      SortOrder `json:"s"`
+	CreatedAt time.Time `json:"t"`
+	ID        int       `json:"i"`
+}
+
+// errInvalidCursor is returned by DecodeCursor for malformed input
+var errInvalidCursor = errors.New("invalid cursor")
+
+// NewCursor returns the cursor positioned at q in a listing sorted by order
+func NewCursor(order SortOrder, q *models.Quote) *Cursor {
+	return &Cursor{Sort: order, CreatedAt: q.CreatedAt, ID: q.ID}
+}
+
+// Encode returns the opaque string form of c
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a string produced by Cursor.Encode
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	c := &Cursor{}
+	if err := json.Unmarshal(data, c); err != nil || c.ID <= 0 || c.CreatedAt.IsZero() {
+		return nil, errInvalidCursor
+	}
+	if _, err := ParseSortOrder(string(c.Sort)); err != nil {
+		return nil, errInvalidCursor
+	}
+
+	return c, nil
+}
+
+// follows reports whether q comes strictly after c in c's sort order
+func (c *Cursor) follows(q *models.Quote) bool {
+	if c.Sort == SortOldest {
+		return q.CreatedAt.After(c.CreatedAt) || (q.CreatedAt.Equal(c.CreatedAt) && q.ID > c.ID)
+	}
+	return q.CreatedAt.Before(c.CreatedAt) || (q.CreatedAt.Equal(c.CreatedAt) && q.ID < c.ID)
+}
@@ -1,17 +1,22 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/albantanie/mahfudzot-generator/internal/config"
 	"github.com/albantanie/mahfudzot-generator/internal/models"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 // DB represents the database connection
 type DB struct {
 	*sql.DB
+	queryTimeout time.Duration
+	randomIDs    *idCache
+	corpus       *statsCache
 }
 
 // New creates a new database connection
@@ -28,241 +33,260 @@ func New(cfg *config.DatabaseConfig) (*DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &DB{db}, nil
+	return &DB{DB: db, queryTimeout: cfg.QueryTimeout, randomIDs: &idCache{}, corpus: &statsCache{}}, nil
+}
+
+// withTimeout bounds ctx by the configured per-query timeout
+func (db *DB) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if db.queryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, db.queryTimeout)
 }
 
 // QuoteRepository defines the interface for quote operations
 type QuoteRepository interface {
-	GetAll(limit, offset int) ([]*models.Quote, error)
-	GetByID(id int) (*models.Quote, error)
-	GetRandom() (*models.Quote, error)
-	GetByAuthor(author string, limit, offset int) ([]*models.Quote, error)
-	GetByCategory(category string, limit, offset int) ([]*models.Quote, error)
-	Create(quote *models.QuoteRequest) (*models.Quote, error)
-	Update(id int, quote *models.QuoteRequest) (*models.Quote, error)
-	Delete(id int) error
-	Count() (int, error)
+	Find(ctx context.Context, filter QuoteFilter) ([]*models.Quote, error)
+	GetByID(ctx context.Context, id int) (*models.Quote, error)
+	GetRandom(ctx context.Context, opts RandomOptions) ([]*models.Quote, error)
+	Create(ctx context.Context, quote *models.QuoteRequest) (*models.Quote, error)
+	Update(ctx context.Context, id int, quote *models.QuoteRequest) (*models.Quote, error)
+	Delete(ctx context.Context, id int) error
+	Count(ctx context.Context, filter QuoteFilter) (int, error)
+	Search(ctx context.Context, filter QuoteFilter) (*SearchPage, error)
+	Facets(ctx context.Context, filter QuoteFilter) (*models.Facets, error)
 }
 
-// GetAll retrieves all quotes with pagination
-func (db *DB) GetAll(limit, offset int) ([]*models.Quote, error) {
-	query := `
-		SELECT id, text_arabic, text_latin, translation, author, category, source, created_at, updated_at
-		FROM quotes
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`
-
-	rows, err := db.Query(query, limit, offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+// quoteColumns lists the columns read by scanQuote, in order
+const quoteColumns = `id, text_arabic, COALESCE(text_latin, ''), COALESCE(translation, ''), author,
+		author_id, COALESCE(category, ''), COALESCE(source, ''),
+		ARRAY(SELECT t.name FROM quote_tags qt JOIN tags t ON t.id = qt.tag_id WHERE qt.quote_id = quotes.id
+			ORDER BY LOWER(t.name) = LOWER(COALESCE(quotes.category, '')) DESC, t.name),
+		created_at, updated_at`
 
-	var quotes []*models.Quote
-	for rows.Next() {
-		quote := &models.Quote{}
-		err := rows.Scan(
-			&quote.ID,
-			&quote.TextArabic,
-			&quote.TextLatin,
-			&quote.Translation,
-			&quote.Author,
-			&quote.Category,
-			&quote.Source,
-			&quote.CreatedAt,
-			&quote.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		quotes = append(quotes, quote)
-	}
-
-	return quotes, nil
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
 }
 
-// GetByID retrieves a quote by its ID
-func (db *DB) GetByID(id int) (*models.Quote, error) {
-	query := `
-		SELECT id, text_arabic, text_latin, translation, author, category, source, created_at, updated_at
-		FROM quotes
-		WHERE id = $1
-	`
-
+// scanQuote reads a row selected with quoteColumns
+func scanQuote(row rowScanner) (*models.Quote, error) {
 	quote := &models.Quote{}
-	err := db.QueryRow(query, id).Scan(
+	err := row.Scan(
 		&quote.ID,
 		&quote.TextArabic,
 		&quote.TextLatin,
 		&quote.Translation,
 		&quote.Author,
+		&quote.AuthorID,
 		&quote.Category,
 		&quote.Source,
+		pq.Array(&quote.Tags),
 		&quote.CreatedAt,
 		&quote.UpdatedAt,
 	)
-
 	if err != nil {
 		return nil, err
 	}
-
+	if quote.Tags == nil {
+		quote.Tags = []string{}
+	}
 	return quote, nil
 }
 
-// GetRandom retrieves a random quote
-func (db *DB) GetRandom() (*models.Quote, error) {
-	query := `
-		SELECT id, text_arabic, text_latin, translation, author, category, source, created_at, updated_at
-		FROM quotes
-		ORDER BY RANDOM()
-		LIMIT 1
-	`
-
-	quote := &models.Quote{}
-	err := db.QueryRow(query).Scan(
-		&quote.ID,
-		&quote.TextArabic,
-		&quote.TextLatin,
-		&quote.Translation,
-		&quote.Author,
-		&quote.Category,
-		&quote.Source,
-		&quote.CreatedAt,
-		&quote.UpdatedAt,
-	)
+// scanQuotes reads every row selected with quoteColumns
+func scanQuotes(rows *sql.Rows) ([]*models.Quote, error) {
+	defer rows.Close()
 
-	if err != nil {
-		return nil, err
+	quotes := []*models.Quote{}
+	for rows.Next() {
+		quote, err := scanQuote(rows)
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, quote)
 	}
 
-	return quote, nil
+	return quotes, rows.Err()
 }
 
-// GetByAuthor retrieves quotes by author with pagination
-func (db *DB) GetByAuthor(author string, limit, offset int) ([]*models.Quote, error) {
-	query := `
-		SELECT id, text_arabic, text_latin, translation, author, category, source, created_at, updated_at
-		FROM quotes
-		WHERE author ILIKE $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
-	`
-
-	rows, err := db.Query(query, "%"+author+"%", limit, offset)
-	if err != nil {
+// Find retrieves the quotes matching filter
+func (db *DB) Find(ctx context.Context, filter QuoteFilter) ([]*models.Quote, error) {
+	if err := filter.validate(); err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var quotes []*models.Quote
-	for rows.Next() {
-		quote := &models.Quote{}
-		err := rows.Scan(
-			&quote.ID,
-			&quote.TextArabic,
-			&quote.TextLatin,
-			&quote.Translation,
-			&quote.Author,
-			&quote.Category,
-			&quote.Source,
-			&quote.CreatedAt,
-			&quote.UpdatedAt,
-		)
+	if filter.Latin != "" {
+		quotes, err := db.findAll(ctx, filter)
 		if err != nil {
 			return nil, err
 		}
-		quotes = append(quotes, quote)
+		return filter.paginate(quotes), nil
+	}
+
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	where, args := filter.where()
+	query := "SELECT " + quoteColumns + " FROM quotes " + where + " " + filter.orderBy()
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err, "quotes")
+	}
+
+	quotes, err := scanQuotes(rows)
+	if err != nil {
+		return nil, translateError(err, "quotes")
 	}
 
 	return quotes, nil
 }
 
-// GetByCategory retrieves quotes by category with pagination
-func (db *DB) GetByCategory(category string, limit, offset int) ([]*models.Quote, error) {
+// findAll returns every quote matching filter, sorted but not paginated. The
+// SQL criteria narrow the rows before a Latin query is applied in Go.
+func (db *DB) findAll(ctx context.Context, filter QuoteFilter) ([]*models.Quote, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	where, args := filter.where()
+	rows, err := db.QueryContext(ctx, "SELECT "+quoteColumns+" FROM quotes "+where+" "+filter.orderBy(), args...)
+	if err != nil {
+		return nil, translateError(err, "quotes")
+	}
+
+	quotes, err := scanQuotes(rows)
+	if err != nil {
+		return nil, translateError(err, "quotes")
+	}
+
+	if filter.Latin == "" {
+		return quotes, nil
+	}
+
+	matches := quotes[:0]
+	for _, quote := range quotes {
+		if filter.matches(quote) {
+			matches = append(matches, quote)
+		}
+	}
+	return matches, nil
+}
+
+// GetByID retrieves a quote by its ID
+func (db *DB) GetByID(ctx context.Context, id int) (*models.Quote, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
 	query := `
-		SELECT id, text_arabic, text_latin, translation, author, category, source, created_at, updated_at
+		SELECT ` + quoteColumns + `
 		FROM quotes
-		WHERE category ILIKE $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
+		WHERE id = $1
 	`
 
-	rows, err := db.Query(query, "%"+category+"%", limit, offset)
+	quote, err := scanQuote(db.QueryRowContext(ctx, query, id))
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, fmt.Sprintf("quote with id %d", id))
 	}
-	defer rows.Close()
 
-	var quotes []*models.Quote
-	for rows.Next() {
-		quote := &models.Quote{}
-		err := rows.Scan(
-			&quote.ID,
-			&quote.TextArabic,
-			&quote.TextLatin,
-			&quote.Translation,
-			&quote.Author,
-			&quote.Category,
-			&quote.Source,
-			&quote.CreatedAt,
-			&quote.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		quotes = append(quotes, quote)
+	return quote, nil
+}
+
+// canonicalRequest returns a copy of req whose category is spelled as in
+// the taxonomy or the tags, see canonicalCategory
+func canonicalRequest(ctx context.Context, tx *sql.Tx, req *models.QuoteRequest) (*models.QuoteRequest, error) {
+	category, err := canonicalCategory(ctx, tx, req.Category)
+	if err != nil {
+		return nil, err
 	}
 
-	return quotes, nil
+	canonical := *req
+	canonical.Category = category
+	return &canonical, nil
 }
 
-// Create creates a new quote
-func (db *DB) Create(req *models.QuoteRequest) (*models.Quote, error) {
+// Create creates a new quote and its tags
+func (db *DB) Create(ctx context.Context, req *models.QuoteRequest) (*models.Quote, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, translateError(err, "quote")
+	}
+	defer tx.Rollback()
+
+	req, err = canonicalRequest(ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		INSERT INTO quotes (text_arabic, text_latin, translation, author, category, source)
 		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, text_arabic, text_latin, translation, author, category, source, created_at, updated_at
-	`
+		RETURNING id`
 
-	quote := &models.Quote{}
-	err := db.QueryRow(query,
+	var id int
+	err = tx.QueryRowContext(ctx, query,
 		req.TextArabic,
 		req.TextLatin,
 		req.Translation,
 		req.Author,
 		req.Category,
 		req.Source,
-	).Scan(
-		&quote.ID,
-		&quote.TextArabic,
-		&quote.TextLatin,
-		&quote.Translation,
-		&quote.Author,
-		&quote.Category,
-		&quote.Source,
-		&quote.CreatedAt,
-		&quote.UpdatedAt,
-	)
-
+	).Scan(&id)
 	if err != nil {
+		return nil, translateError(err, "quote")
+	}
+
+	if err := setQuoteTags(ctx, tx, id, req.TagNames()); err != nil {
 		return nil, err
 	}
 
+	quote, err := scanQuote(tx.QueryRowContext(ctx, "SELECT "+quoteColumns+" FROM quotes WHERE id = $1", id))
+	if err != nil {
+		return nil, translateError(err, "quote")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, translateError(err, "quote")
+	}
+
+	db.randomIDs.invalidate()
+	db.corpus.invalidate()
 	return quote, nil
 }
 
-// Update updates an existing quote
-func (db *DB) Update(id int, req *models.QuoteRequest) (*models.Quote, error) {
+// Update updates an existing quote, replacing its tags
+func (db *DB) Update(ctx context.Context, id int, req *models.QuoteRequest) (*models.Quote, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, translateError(err, "quote")
+	}
+	defer tx.Rollback()
+
+	req, err = canonicalRequest(ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		UPDATE quotes
 		SET text_arabic = $2, text_latin = $3, translation = $4, author = $5, category = $6, source = $7
 		WHERE id = $1
-		RETURNING id, text_arabic, text_latin, translation, author, category, source, created_at, updated_at
-	`
+		RETURNING id`
 
-	quote := &models.Quote{}
-	err := db.QueryRow(query,
+	err = tx.QueryRowContext(ctx, query,
 		id,
 		req.TextArabic,
 		req.TextLatin,
@@ -270,48 +294,68 @@ func (db *DB) Update(id int, req *models.QuoteRequest) (*models.Quote, error) {
 		req.Author,
 		req.Category,
 		req.Source,
-	).Scan(
-		&quote.ID,
-		&quote.TextArabic,
-		&quote.TextLatin,
-		&quote.Translation,
-		&quote.Author,
-		&quote.Category,
-		&quote.Source,
-		&quote.CreatedAt,
-		&quote.UpdatedAt,
-	)
-
+	).Scan(&id)
 	if err != nil {
+		return nil, translateError(err, fmt.Sprintf("quote with id %d", id))
+	}
+
+	if err := setQuoteTags(ctx, tx, id, req.TagNames()); err != nil {
 		return nil, err
 	}
 
+	quote, err := scanQuote(tx.QueryRowContext(ctx, "SELECT "+quoteColumns+" FROM quotes WHERE id = $1", id))
+	if err != nil {
+		return nil, translateError(err, fmt.Sprintf("quote with id %d", id))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, translateError(err, "quote")
+	}
+
+	db.corpus.invalidate()
 	return quote, nil
 }
 
 // Delete deletes a quote by ID
-func (db *DB) Delete(id int) error {
+func (db *DB) Delete(ctx context.Context, id int) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
 	query := "DELETE FROM quotes WHERE id = $1"
-	result, err := db.Exec(query, id)
+	result, err := db.ExecContext(ctx, query, id)
 	if err != nil {
-		return err
+		return translateError(err, fmt.Sprintf("quote with id %d", id))
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return err
+		return translateError(err, fmt.Sprintf("quote with id %d", id))
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("quote with id %d not found", id)
+		return newError(ErrNotFound, "quote with id %d not found", id)
 	}
 
+	db.randomIDs.invalidate()
+	db.corpus.invalidate()
 	return nil
 }
 
-// Count returns the total number of quotes
-func (db *DB) Count() (int, error) {
+// Count returns the number of quotes matching filter, ignoring its sort,
+// cursor, limit and offset
+func (db *DB) Count(ctx context.Context, filter QuoteFilter) (int, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	filter.After = nil
+	if filter.Latin != "" {
+		quotes, err := db.findAll(ctx, filter)
+		return len(quotes), err
+	}
+
+	where, args := filter.where()
+
 	var count int
-	err := db.QueryRow("SELECT COUNT(*) FROM quotes").Scan(&count)
-	return count, err
+	err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM quotes "+where, args...).Scan(&count)
+	return count, translateError(err, "quotes")
 }
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/lib/pq"
+)
+
+// Error kinds returned by every repository implementation. Use errors.Is to
+// test for them; the concrete error is always an *Error.
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrConflict    = errors.New("conflict")
+	ErrValidation  = errors.New("validation failed")
+	ErrUnavailable = errors.New("database unavailable")
+)
+
+// Error is a repository error whose Message is safe to show to API clients.
+// The underlying driver error, if any, is kept in Err for logging.
+type Error struct {
+	Kind    error
+	Message string
+	Err     error
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Is reports whether target is the kind of e
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+// Unwrap returns the underlying driver error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// newError creates an *Error of the given kind with a formatted client message
+func newError(kind error, format string, args ...interface{}) *Error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// PostgreSQL error classes and codes, see
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pqClassDataException        = "22"
+	pqClassIntegrityConstraint  = "23"
+	pqClassConnection           = "08"
+	pqClassResources            = "53"
+	pqClassOperatorIntervention = "57"
+	pqUniqueViolation           = "23505"
+)
+
+// translateError converts a database/sql or lib/pq error into an *Error. The
+// subject names what was being accessed, e.g. "quote with id 7".
+func translateError(err error, subject string) error {
+	if err == nil {
+		return nil
+	}
+
+	var repoErr *Error
+	if errors.As(err, &repoErr) {
+		return err
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return &Error{Kind: ErrNotFound, Message: subject + " not found", Err: err}
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Code == pqUniqueViolation:
+			return &Error{Kind: ErrConflict, Message: subject + " already exists", Err: err}
+		case pqErr.Code.Class() == pqClassIntegrityConstraint, pqErr.Code.Class() == pqClassDataException:
+			return &Error{Kind: ErrValidation, Message: "invalid " + subject, Err: err}
+		case pqErr.Code.Class() == pqClassConnection,
+			pqErr.Code.Class() == pqClassResources,
+			pqErr.Code.Class() == pqClassOperatorIntervention:
+			return &Error{Kind: ErrUnavailable, Message: "database is unavailable", Err: err}
+		}
+		return err
+	}
+
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) {
+		return &Error{Kind: ErrUnavailable, Message: "database is unavailable", Err: err}
+	}
+
+	return err
+}
+
+// contextError returns the repository error for a cancelled or expired ctx,
+// or nil if ctx is still active
+func contextError(ctx context.Context) error {
+	err := ctx.Err()
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &Error{Kind: ErrUnavailable, Message: "database is unavailable", Err: err}
+	}
+	return err
+}
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"sort"
+
+	"github.com/albantanie/mahfudzot-generator/internal/models"
+)
+
+// facetCounts accumulates the number of quotes per value of each facet
+type facetCounts map[SearchField]map[string]int
+
+// add counts n quotes with value for field; empty values are not counted
+func (c facetCounts) add(field SearchField, value string, n int) {
+	if value == "" {
+		return
+	}
+	if c[field] == nil {
+		c[field] = make(map[string]int)
+	}
+	c[field][value] += n
+}
+
+// addQuote counts q under each facet
+func (c facetCounts) addQuote(q *models.Quote) {
+	c.add(FieldAuthor, q.Author, 1)
+	c.add(FieldCategory, q.Category, 1)
+	c.add(FieldSource, q.Source, 1)
+}
+
+// facets returns the counts ordered by descending count, then value
+func (c facetCounts) facets() *models.Facets {
+	return &models.Facets{
+		Author:   sortedFacet(c[FieldAuthor]),
+		Category: sortedFacet(c[FieldCategory]),
+		Source:   sortedFacet(c[FieldSource]),
+	}
+}
+
+func sortedFacet(counts map[string]int) []models.FacetCount {
+	facet := make([]models.FacetCount, 0, len(counts))
+	for value, count := range counts {
+		facet = append(facet, models.FacetCount{Value: value, Count: count})
+	}
+	sort.Slice(facet, func(i, j int) bool {
+		if facet[i].Count != facet[j].Count {
+			return facet[i].Count > facet[j].Count
+		}
+		return facet[i].Value < facet[j].Value
+	})
+	return facet
+}
+
+// Facets counts the quotes matching filter per author, category and source,
+// ignoring its sort, cursor, limit and offset. The counts are aggregated in
+// a single grouped query over a lateral unpivot of the three columns.
+func (db *DB) Facets(ctx context.Context, filter QuoteFilter) (*models.Facets, error) {
+	filter.After = nil
+	counts := make(facetCounts)
+
+	if filter.Latin != "" {
+		quotes, err := db.findAll(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, quote := range quotes {
+			counts.addQuote(quote)
+		}
+		return counts.facets(), nil
+	}
+
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	where, args := filter.where()
+	if where == "" {
+		where = "WHERE "
+	} else {
+		where += " AND "
+	}
+
+	query := `
+		SELECT f.field, f.value, COUNT(*)
+		FROM quotes
+		CROSS JOIN LATERAL (VALUES ('author', author), ('category', category), ('source', source)) AS f(field, value)
+		` + where + `f.value <> ''
+		GROUP BY f.field, f.value`
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err, "quotes")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var field, value string
+		var count int
+		if err := rows.Scan(&field, &value, &count); err != nil {
+			return nil, translateError(err, "quotes")
+		}
+		counts.add(SearchField(field), value, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err, "quotes")
+	}
+
+	return counts.facets(), nil
+}
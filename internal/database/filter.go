@@ -0,0 +1,396 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/albantanie/mahfudzot-generator/internal/models"
+	"github.com/albantanie/mahfudzot-generator/internal/translit"
+	"github.com/lib/pq"
+)
+
+// SortOrder selects the order in which Find returns quotes
+type SortOrder string
+
+// Supported sort orders. Ties are always broken by id in the same direction.
+// SortRelevance only applies to Search, which puts the best matches first
+// and breaks ties newest first; Find orders such filters by SortNewest.
+const (
+	SortNewest    SortOrder = "newest"
+	SortOldest    SortOrder = "oldest"
+	SortRelevance SortOrder = "relevance"
+)
+
+// SearchField names what a SearchTerm is matched against
+type SearchField string
+
+// Search fields. FieldText covers text_arabic, text_latin and translation.
+const (
+	FieldText     SearchField = "text"
+	FieldAuthor   SearchField = "author"
+	FieldCategory SearchField = "category"
+	FieldSource   SearchField = "source"
+)
+
+// SearchTerm requires Value to occur in Field, or with Negate to be absent
+// from it. Text values are normalized like Query and may contain spaces to
+// match a phrase; other fields match case-insensitively anywhere.
+type SearchTerm struct {
+	Field  SearchField
+	Value  string
+	Negate bool
+}
+
+// SearchClause holds when any of its terms does
+type SearchClause []SearchTerm
+
+// QuoteFilter describes which quotes Find returns. String criteria match
+// case-insensitively anywhere in the field; empty criteria are ignored and
+// all criteria must hold. DB and MockDB apply exactly the same semantics.
+type QuoteFilter struct {
+	Author string
+	// AuthorID matches the author entity exactly when positive
+	AuthorID int
+	Category string
+	// Categories matches any of the categories exactly, ignoring case, when
+	// not empty
+	Categories []string
+	// InCategories matches quotes filed under any of the categories, as
+	// their category or as one of their tags, ignoring case
+	InCategories []string
+	Source       string
+	// AnyTags matches quotes with at least one of the tags, AllTags quotes
+	// with every one of them; tags compare ignoring case
+	AnyTags []string
+	AllTags []string
+	// Text matches text_arabic, text_latin or translation
+	Text string
+	// Query is an Arabic-aware search: every whitespace-separated term must
+	// occur in text_arabic, text_latin or translation after normalization
+	// (see normalizeArabic)
+	Query string
+	// Clauses must all hold; see SearchClause
+	Clauses []SearchClause
+	// Latin is a transliteration-tolerant search on text_latin (see
+	// translit.Match). It cannot be expressed in SQL, so both backends apply
+	// it in Go.
+	Latin string
+	// MaxLength limits text_arabic to this many characters when positive
+	MaxLength int
+	// CreatedFrom is inclusive, CreatedTo is exclusive
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Sort        SortOrder
+	// After restricts the results to quotes following the cursor in Sort
+	// order; its Sort must match the filter's and cannot be SortRelevance
+	After *Cursor
+	// Limit of zero or less returns every match
+	Limit  int
+	Offset int
+}
+
+// ParseSortOrder converts a string to a SortOrder; empty means SortNewest
+func ParseSortOrder(s string) (SortOrder, error) {
+	switch SortOrder(strings.ToLower(s)) {
+	case "", SortNewest:
+		return SortNewest, nil
+	case SortOldest:
+		return SortOldest, nil
+	case SortRelevance:
+		return SortRelevance, nil
+	}
+	return "", fmt.Errorf("unknown sort order %q (expected newest, oldest or relevance)", s)
+}
+
+// anyTagCondition matches quotes having one of the lowercase tags in its
+// array placeholder
+const anyTagCondition = `id IN (SELECT qt.quote_id FROM quote_tags qt JOIN tags t ON t.id = qt.tag_id
+			WHERE LOWER(t.name) = ANY(?))`
+
+// where returns the SQL WHERE clause (including the keyword, or empty) and
+// its arguments, numbered from $1
+func (f *QuoteFilter) where() (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+
+	// add appends a condition, numbering each ? placeholder in turn
+	add := func(cond string, values ...interface{}) {
+		for _, v := range values {
+			args = append(args, v)
+			cond = strings.Replace(cond, "?", fmt.Sprintf("$%d", len(args)), 1)
+		}
+		conds = append(conds, cond)
+	}
+
+	if f.Author != "" {
+		add("author ILIKE ?", likePattern(f.Author))
+	}
+	if f.AuthorID > 0 {
+		add("author_id = ?", f.AuthorID)
+	}
+	if f.Category != "" {
+		add("COALESCE(category, '') ILIKE ?", likePattern(f.Category))
+	}
+	if len(f.Categories) > 0 {
+		lowered := make([]string, len(f.Categories))
+		for i, category := range f.Categories {
+			lowered[i] = strings.ToLower(category)
+		}
+		add("LOWER(COALESCE(category, '')) = ANY(?)", pq.Array(lowered))
+	}
+	if tags := lowerTags(f.AnyTags); len(tags) > 0 {
+		add(anyTagCondition, pq.Array(tags))
+	}
+	if categories := lowerTags(f.InCategories); len(categories) > 0 {
+		// The category of a quote is always one of its tags
+		add(anyTagCondition, pq.Array(categories))
+	}
+	if tags := lowerTags(f.AllTags); len(tags) > 0 {
+		add(`id IN (SELECT qt.quote_id FROM quote_tags qt JOIN tags t ON t.id = qt.tag_id
+			WHERE LOWER(t.name) = ANY(?) GROUP BY qt.quote_id HAVING COUNT(*) = ?)`, pq.Array(tags), len(tags))
+	}
+	if f.Source != "" {
+		add("COALESCE(source, '') ILIKE ?", likePattern(f.Source))
+	}
+	if f.Text != "" {
+		pattern := likePattern(f.Text)
+		add("(text_arabic ILIKE ? OR COALESCE(text_latin, '') ILIKE ? OR COALESCE(translation, '') ILIKE ?)", pattern, pattern, pattern)
+	}
+	for _, term := range searchTerms(f.Query) {
+		add("search_text LIKE ?", likePattern(term))
+	}
+	for _, clause := range f.Clauses {
+		conds := make([]string, len(clause))
+		values := make([]interface{}, len(clause))
+		for i, term := range clause {
+			conds[i], values[i] = term.sql()
+		}
+		add("("+strings.Join(conds, " OR ")+")", values...)
+	}
+	if f.MaxLength > 0 {
+		add("char_length(text_arabic) <= ?", f.MaxLength)
+	}
+	if !f.CreatedFrom.IsZero() {
+		add("created_at >= ?", f.CreatedFrom)
+	}
+	if !f.CreatedTo.IsZero() {
+		add("created_at < ?", f.CreatedTo)
+	}
+	if f.After != nil {
+		op := "<"
+		if f.After.Sort == SortOldest {
+			op = ">"
+		}
+		add("(created_at, id) "+op+" (?, ?)", f.After.CreatedAt, f.After.ID)
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
+
+// orderBy returns the SQL ORDER BY clause for the filter's sort order
+func (f *QuoteFilter) orderBy() string {
+	if f.Sort == SortOldest {
+		return "ORDER BY created_at ASC, id ASC"
+	}
+	return "ORDER BY created_at DESC, id DESC"
+}
+
+// matches reports whether q satisfies every criterion of the filter
+func (f *QuoteFilter) matches(q *models.Quote) bool {
+	if f.Author != "" && !containsFold(q.Author, f.Author) {
+		return false
+	}
+	if f.AuthorID > 0 && q.AuthorID != f.AuthorID {
+		return false
+	}
+	if f.Category != "" && !containsFold(q.Category, f.Category) {
+		return false
+	}
+	if len(f.Categories) > 0 && !equalsAnyFold(q.Category, f.Categories) {
+		return false
+	}
+	if tags := lowerTags(f.AnyTags); len(tags) > 0 && !hasAnyTag(q.Tags, tags) {
+		return false
+	}
+	if categories := lowerTags(f.InCategories); len(categories) > 0 && !hasAnyTag(q.Tags, categories) {
+		return false
+	}
+	for _, tag := range lowerTags(f.AllTags) {
+		if !hasTag(q.Tags, tag) {
+			return false
+		}
+	}
+	if f.Source != "" && !containsFold(q.Source, f.Source) {
+		return false
+	}
+	if f.Text != "" && !containsFold(q.TextArabic, f.Text) &&
+		!containsFold(q.TextLatin, f.Text) && !containsFold(q.Translation, f.Text) {
+		return false
+	}
+	if f.Query != "" && !containsAll(searchDocument(q.TextArabic, q.TextLatin, q.Translation), searchTerms(f.Query)) {
+		return false
+	}
+	for _, clause := range f.Clauses {
+		if !clause.matches(q) {
+			return false
+		}
+	}
+	if f.Latin != "" {
+		if _, ok := translit.Match(f.Latin, q.TextLatin); !ok {
+			return false
+		}
+	}
+	if f.MaxLength > 0 && utf8.RuneCountInString(q.TextArabic) > f.MaxLength {
+		return false
+	}
+	if !f.CreatedFrom.IsZero() && q.CreatedAt.Before(f.CreatedFrom) {
+		return false
+	}
+	if !f.CreatedTo.IsZero() && !q.CreatedAt.Before(f.CreatedTo) {
+		return false
+	}
+	if f.After != nil && !f.After.follows(q) {
+		return false
+	}
+	return true
+}
+
+// SearchText returns the words the filter looks for in the text fields:
+// Query and the values of text terms that are not negated
+func (f *QuoteFilter) SearchText() string {
+	words := []string{f.Query}
+	for _, clause := range f.Clauses {
+		for _, term := range clause {
+			if term.Field == FieldText && !term.Negate {
+				words = append(words, term.Value)
+			}
+		}
+	}
+	return strings.TrimSpace(strings.Join(words, " "))
+}
+
+// requiredText returns the normalized terms and phrases every match must
+// contain in its search document
+func (f *QuoteFilter) requiredText() []string {
+	required := searchTerms(f.Query)
+	for _, clause := range f.Clauses {
+		if len(clause) == 1 && clause[0].Field == FieldText && !clause[0].Negate {
+			required = append(required, normalizeArabic(clause[0].Value))
+		}
+	}
+	return required
+}
+
+// sql returns the SQL condition for the term with a single ? placeholder
+// and its value
+func (t SearchTerm) sql() (string, interface{}) {
+	var cond string
+	value := likePattern(t.Value)
+	switch t.Field {
+	case FieldAuthor:
+		cond = "author ILIKE ?"
+	case FieldCategory:
+		cond = "COALESCE(category, '') ILIKE ?"
+	case FieldSource:
+		cond = "COALESCE(source, '') ILIKE ?"
+	default:
+		cond = "search_text LIKE ?"
+		value = likePattern(normalizeArabic(t.Value))
+	}
+	if t.Negate {
+		cond = "NOT " + cond
+	}
+	return cond, value
+}
+
+// matches reports whether q satisfies the term
+func (t SearchTerm) matches(q *models.Quote) bool {
+	var found bool
+	switch t.Field {
+	case FieldAuthor:
+		found = containsFold(q.Author, t.Value)
+	case FieldCategory:
+		found = containsFold(q.Category, t.Value)
+	case FieldSource:
+		found = containsFold(q.Source, t.Value)
+	default:
+		found = strings.Contains(searchDocument(q.TextArabic, q.TextLatin, q.Translation), normalizeArabic(t.Value))
+	}
+	return found != t.Negate
+}
+
+// matches reports whether q satisfies any term of the clause
+func (c SearchClause) matches(q *models.Quote) bool {
+	for _, term := range c {
+		if term.matches(q) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortQuotes orders quotes in place the same way orderBy does in SQL
+func (f *QuoteFilter) sortQuotes(quotes []*models.Quote) {
+	sort.Slice(quotes, func(i, j int) bool {
+		a, b := quotes[i], quotes[j]
+		if f.Sort == SortOldest {
+			a, b = b, a
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	})
+}
+
+// validate rejects filters whose criteria cannot be combined
+func (f *QuoteFilter) validate() error {
+	if f.Sort == SortRelevance && f.After != nil {
+		return newError(ErrValidation, "cursor pagination is not supported when sorting by relevance")
+	}
+	return nil
+}
+
+// paginate applies the filter's offset and limit to an already sorted slice
+func (f *QuoteFilter) paginate(quotes []*models.Quote) []*models.Quote {
+	start, end := f.bounds(len(quotes))
+	return quotes[start:end]
+}
+
+// bounds returns the range of n sorted results selected by the filter's
+// offset and limit
+func (f *QuoteFilter) bounds(n int) (start, end int) {
+	start = min(max(f.Offset, 0), n)
+	end = n
+	if f.Limit > 0 && start+f.Limit < n {
+		end = start + f.Limit
+	}
+	return start, end
+}
+
+// likePattern builds an ILIKE pattern matching s anywhere, escaping wildcards
+func likePattern(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(s) + "%"
+}
+
+// containsFold reports whether substr is within s, ignoring case
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// equalsAnyFold reports whether s is one of values, ignoring case
+func equalsAnyFold(s string, values []string) bool {
+	for _, value := range values {
+		if strings.EqualFold(s, value) {
+			return true
+		}
+	}
+	return false
+}
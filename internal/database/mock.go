@@ -1,8 +1,10 @@
 package database
 
 import (
-	"fmt"
-	"math/rand"
+	"context"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/albantanie/mahfudzot-generator/internal/models"
@@ -10,156 +12,872 @@ import (
 
 // MockDB represents a mock database for demo purposes
 type MockDB struct {
-	quotes []*models.Quote
+	mu        sync.RWMutex
+	quotes    []*models.Quote
+	nextID    int
+	search    *searchIndex
+	values    map[SearchField]valueIndex
+	tags      map[string]string
+	apiKeys   []*models.APIKey
+	nextKeyID int
+
+	authors      []*models.Author
+	nextAuthorID int
+
+	occasions      []*models.Occasion
+	nextOccasionID int
+
+	categories []*models.Category
 }
 
 // NewMockDB creates a new mock database with comprehensive seed data
 func NewMockDB() *MockDB {
 	seedData := GetSeedData()
-	quotes := make([]*models.Quote, len(seedData))
+
+	m := &MockDB{
+		quotes:         make([]*models.Quote, len(seedData)),
+		nextID:         len(seedData) + 1,
+		search:         newSearchIndex(),
+		values:         map[SearchField]valueIndex{FieldAuthor: {}, FieldCategory: {}, FieldSource: {}},
+		tags:           make(map[string]string),
+		nextKeyID:      1,
+		nextAuthorID:   1,
+		nextOccasionID: 1,
+	}
 
 	// Convert seed data to Quote models
 	for i, seed := range seedData {
-		quotes[i] = &models.Quote{
+		m.quotes[i] = &models.Quote{
 			ID:          i + 1,
 			TextArabic:  seed.TextArabic,
 			TextLatin:   seed.TextLatin,
 			Translation: seed.Translation,
-			Author:      seed.Author,
 			Category:    seed.Category,
 			Source:      seed.Source,
+			Tags:        m.quoteTags(seed),
 			CreatedAt:   time.Now().Add(-time.Duration(i) * time.Hour),
 			UpdatedAt:   time.Now().Add(-time.Duration(i) * time.Hour),
 		}
+		m.quotes[i].Author, m.quotes[i].AuthorID = m.resolveAuthor(seed.Author)
+		m.index(m.quotes[i])
 	}
-
-	return &MockDB{quotes: quotes}
-}
-
-// GetAll retrieves all quotes with pagination
-func (m *MockDB) GetAll(limit, offset int) ([]*models.Quote, error) {
-	if offset >= len(m.quotes) {
-		return []*models.Quote{}, nil
+	for _, author := range m.authors {
+		author.Aliases = uniqueAliases(author.Name, GetSeedAliases()[author.Name])
+	}
+	for _, req := range DefaultOccasions() {
+		m.CreateOccasion(context.Background(), req)
 	}
 
-	end := offset + limit
-	if end > len(m.quotes) {
-		end = len(m.quotes)
+	slugIDs := make(map[string]int)
+	for i, def := range DefaultCategories() {
+		category := &models.Category{
+			ID:             i + 1,
+			Slug:           def.Slug,
+			Name:           def.Name,
+			NameArabic:     def.NameArabic,
+			NameIndonesian: def.NameIndonesian,
+			CreatedAt:      time.Now(),
+		}
+		if parentID, ok := slugIDs[def.Parent]; ok {
+			category.ParentID = &parentID
+		}
+		slugIDs[def.Slug] = category.ID
+		m.categories = append(m.categories, category)
 	}
 
-	return m.quotes[offset:end], nil
+	return m
 }
 
-// GetByID retrieves a quote by its ID
-func (m *MockDB) GetByID(id int) (*models.Quote, error) {
+// index adds quote to the search and value indexes. The caller must hold
+// m.mu.
+func (m *MockDB) index(quote *models.Quote) {
+	m.search.put(quote.ID, searchDocument(quote.TextArabic, quote.TextLatin, quote.Translation))
+	m.values[FieldAuthor].add(quote.Author)
+	m.values[FieldCategory].add(quote.Category)
+	m.values[FieldSource].add(quote.Source)
+}
+
+// unindex removes quote from the search and value indexes. The caller must
+// hold m.mu.
+func (m *MockDB) unindex(quote *models.Quote) {
+	m.search.remove(quote.ID)
+	m.values[FieldAuthor].remove(quote.Author)
+	m.values[FieldCategory].remove(quote.Category)
+	m.values[FieldSource].remove(quote.Source)
+}
+
+// matching returns the quotes matching filter in storage order, using the
+// search index to narrow down candidates for search queries. The caller must
+// hold m.mu.
+func (m *MockDB) matching(filter QuoteFilter) []*models.Quote {
+	var candidates map[int]struct{}
+	if required := filter.requiredText(); len(required) > 0 {
+		candidates = m.search.lookup(required)
+	}
+
+	matches := []*models.Quote{}
 	for _, quote := range m.quotes {
-		if quote.ID == id {
-			return quote, nil
+		if candidates != nil {
+			if _, ok := candidates[quote.ID]; !ok {
+				continue
+			}
+		}
+		if filter.matches(quote) {
+			matches = append(matches, quote)
 		}
 	}
-	return nil, fmt.Errorf("quote with id %d not found", id)
+	return matches
 }
 
-// GetRandom retrieves a random quote
-func (m *MockDB) GetRandom() (*models.Quote, error) {
-	if len(m.quotes) == 0 {
-		return nil, fmt.Errorf("no quotes available")
+// Find retrieves the quotes matching filter
+func (m *MockDB) Find(ctx context.Context, filter QuoteFilter) ([]*models.Quote, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+	if err := filter.validate(); err != nil {
+		return nil, err
 	}
 
-	randomIndex := rand.Intn(len(m.quotes))
-	return m.quotes[randomIndex], nil
+	filtered := m.matching(filter)
+
+	filter.sortQuotes(filtered)
+	return filter.paginate(filtered), nil
 }
 
-// GetByAuthor retrieves quotes by author with pagination
-func (m *MockDB) GetByAuthor(author string, limit, offset int) ([]*models.Quote, error) {
-	var filtered []*models.Quote
-	for _, quote := range m.quotes {
-		if quote.Author == author {
-			filtered = append(filtered, quote)
-		}
-	}
+// Search returns a page of the quotes matching filter with their relevance
+// to its text (see SearchText) or Latin search, ordered by filter.Sort and
+// paginated like Find, together with their total and facets
+func (m *MockDB) Search(ctx context.Context, filter QuoteFilter) (*SearchPage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 
-	if offset >= len(filtered) {
-		return []*models.Quote{}, nil
+	if err := contextError(ctx); err != nil {
+		return nil, err
 	}
-
-	end := offset + limit
-	if end > len(filtered) {
-		end = len(filtered)
+	if err := filter.validate(); err != nil {
+		return nil, err
 	}
 
-	return filtered[offset:end], nil
+	all := filter
+	all.After = nil
+	filtered := m.matching(all)
+	filter.sortQuotes(filtered)
+
+	var stats *corpusStats
+	if filter.Latin == "" {
+		stats = newCorpusStats(m.quotes)
+	}
+	return filter.searchPage(filtered, stats), nil
 }
 
-// GetByCategory retrieves quotes by category with pagination
-func (m *MockDB) GetByCategory(category string, limit, offset int) ([]*models.Quote, error) {
-	var filtered []*models.Quote
+// GetByID retrieves a quote by its ID
+func (m *MockDB) GetByID(ctx context.Context, id int) (*models.Quote, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+
 	for _, quote := range m.quotes {
-		if quote.Category == category {
-			filtered = append(filtered, quote)
+		if quote.ID == id {
+			return quote, nil
 		}
 	}
+	return nil, newError(ErrNotFound, "quote with id %d not found", id)
+}
+
+// GetRandom retrieves distinct random quotes matching opts, picking from
+// the matching ids in ascending order exactly like DB does
+func (m *MockDB) GetRandom(ctx context.Context, opts RandomOptions) ([]*models.Quote, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 
-	if offset >= len(filtered) {
-		return []*models.Quote{}, nil
+	if err := contextError(ctx); err != nil {
+		return nil, err
 	}
 
-	end := offset + limit
-	if end > len(filtered) {
-		end = len(filtered)
+	filter := opts.Filter
+	filter.After = nil
+
+	byID := make(map[int]*models.Quote)
+	ids := []int{}
+	for _, quote := range m.matching(filter) {
+		byID[quote.ID] = quote
+		ids = append(ids, quote.ID)
+	}
+	ids = excludeIDs(ids, opts.Exclude)
+	if len(ids) == 0 {
+		return nil, newError(ErrNotFound, "no matching quotes available")
+	}
+	sort.Ints(ids)
+
+	picked := pickIDs(ids, opts.Count, opts.Seed)
+	quotes := make([]*models.Quote, len(picked))
+	for i, id := range picked {
+		quotes[i] = byID[id]
 	}
 
-	return filtered[offset:end], nil
+	return quotes, nil
 }
 
 // Create creates a new quote (mock implementation)
-func (m *MockDB) Create(req *models.QuoteRequest) (*models.Quote, error) {
-	newID := len(m.quotes) + 1
+func (m *MockDB) Create(ctx context.Context, req *models.QuoteRequest) (*models.Quote, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+
+	req = m.canonicalRequest(req)
 	quote := &models.Quote{
-		ID:          newID,
+		ID:          m.nextID,
 		TextArabic:  req.TextArabic,
 		TextLatin:   req.TextLatin,
 		Translation: req.Translation,
-		Author:      req.Author,
 		Category:    req.Category,
 		Source:      req.Source,
+		Tags:        m.quoteTags(req),
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
+	quote.Author, quote.AuthorID = m.resolveAuthor(req.Author)
 
+	m.nextID++
 	m.quotes = append(m.quotes, quote)
+	m.index(quote)
 	return quote, nil
 }
 
 // Update updates an existing quote (mock implementation)
-func (m *MockDB) Update(id int, req *models.QuoteRequest) (*models.Quote, error) {
+func (m *MockDB) Update(ctx context.Context, id int, req *models.QuoteRequest) (*models.Quote, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+
+	req = m.canonicalRequest(req)
 	for i, quote := range m.quotes {
 		if quote.ID == id {
-			m.quotes[i].TextArabic = req.TextArabic
-			m.quotes[i].TextLatin = req.TextLatin
-			m.quotes[i].Translation = req.Translation
-			m.quotes[i].Author = req.Author
-			m.quotes[i].Category = req.Category
-			m.quotes[i].Source = req.Source
-			m.quotes[i].UpdatedAt = time.Now()
+			// Replace rather than mutate so readers holding the old pointer are unaffected
+			updated := *quote
+			updated.TextArabic = req.TextArabic
+			updated.TextLatin = req.TextLatin
+			updated.Translation = req.Translation
+			updated.Author, updated.AuthorID = m.resolveAuthor(req.Author)
+			updated.Category = req.Category
+			updated.Source = req.Source
+			updated.Tags = m.quoteTags(req)
+			updated.UpdatedAt = time.Now()
+			m.quotes[i] = &updated
+			m.unindex(quote)
+			m.index(&updated)
 			return m.quotes[i], nil
 		}
 	}
-	return nil, fmt.Errorf("quote with id %d not found", id)
+	return nil, newError(ErrNotFound, "quote with id %d not found", id)
 }
 
 // Delete deletes a quote by ID (mock implementation)
-func (m *MockDB) Delete(id int) error {
+func (m *MockDB) Delete(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := contextError(ctx); err != nil {
+		return err
+	}
+
 	for i, quote := range m.quotes {
 		if quote.ID == id {
-			m.quotes = append(m.quotes[:i], m.quotes[i+1:]...)
+			remaining := make([]*models.Quote, 0, len(m.quotes)-1)
+			remaining = append(remaining, m.quotes[:i]...)
+			m.quotes = append(remaining, m.quotes[i+1:]...)
+			m.unindex(quote)
 			return nil
 		}
 	}
-	return fmt.Errorf("quote with id %d not found", id)
+	return newError(ErrNotFound, "quote with id %d not found", id)
+}
+
+// Count returns the number of quotes matching filter, ignoring its sort,
+// cursor, limit and offset
+func (m *MockDB) Count(ctx context.Context, filter QuoteFilter) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := contextError(ctx); err != nil {
+		return 0, err
+	}
+
+	filter.After = nil
+	return len(m.matching(filter)), nil
+}
+
+// Facets counts the quotes matching filter per author, category and source
+// in a single pass, ignoring its sort, cursor, limit and offset
+func (m *MockDB) Facets(ctx context.Context, filter QuoteFilter) (*models.Facets, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+
+	filter.After = nil
+	counts := make(facetCounts)
+	for _, quote := range m.matching(filter) {
+		counts.addQuote(quote)
+	}
+	return counts.facets(), nil
+}
+
+// CreateAPIKey stores a new API key (mock implementation)
+func (m *MockDB) CreateAPIKey(ctx context.Context, name, role, keyHash, prefix string) (*models.APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, key := range m.apiKeys {
+		if key.KeyHash == keyHash {
+			return nil, newError(ErrConflict, "api key already exists")
+		}
+	}
+
+	key := &models.APIKey{
+		ID:        m.nextKeyID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   keyHash,
+		Role:      role,
+		CreatedAt: time.Now(),
+	}
+
+	m.nextKeyID++
+	m.apiKeys = append(m.apiKeys, key)
+	return key, nil
+}
+
+// GetAPIKeyByHash retrieves an API key by the hash of its clear text (mock implementation)
+func (m *MockDB) GetAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, key := range m.apiKeys {
+		if key.KeyHash == keyHash {
+			return key, nil
+		}
+	}
+	return nil, newError(ErrNotFound, "api key not found")
+}
+
+// ListAPIKeys retrieves all API keys, including revoked ones (mock implementation)
+func (m *MockDB) ListAPIKeys(ctx context.Context) ([]*models.APIKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+
+	keys := make([]*models.APIKey, len(m.apiKeys))
+	copy(keys, m.apiKeys)
+	return keys, nil
+}
+
+// RevokeAPIKey marks an API key as revoked (mock implementation)
+func (m *MockDB) RevokeAPIKey(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := contextError(ctx); err != nil {
+		return err
+	}
+
+	for i, key := range m.apiKeys {
+		if key.ID == id && key.RevokedAt == nil {
+			revoked := *key
+			now := time.Now()
+			revoked.RevokedAt = &now
+			m.apiKeys[i] = &revoked
+			return nil
+		}
+	}
+	return newError(ErrNotFound, "active api key with id %d not found", id)
+}
+
+// ListOccasions retrieves all occasions (mock implementation)
+func (m *MockDB) ListOccasions(ctx context.Context) ([]*models.Occasion, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+
+	occasions := make([]*models.Occasion, len(m.occasions))
+	copy(occasions, m.occasions)
+	sort.SliceStable(occasions, func(i, j int) bool {
+		a, b := occasions[i], occasions[j]
+		if a.StartMonth != b.StartMonth {
+			return a.StartMonth < b.StartMonth
+		}
+		if a.StartDay != b.StartDay {
+			return a.StartDay < b.StartDay
+		}
+		return a.ID < b.ID
+	})
+	return occasions, nil
+}
+
+// CreateOccasion creates a new occasion (mock implementation)
+func (m *MockDB) CreateOccasion(ctx context.Context, req *models.OccasionRequest) (*models.Occasion, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+
+	occasion := &models.Occasion{
+		ID:         m.nextOccasionID,
+		Name:       req.Name,
+		StartMonth: req.StartMonth,
+		StartDay:   req.StartDay,
+		EndMonth:   req.EndMonth,
+		EndDay:     req.EndDay,
+		Category:   req.Category,
+		CreatedAt:  time.Now(),
+	}
+
+	m.nextOccasionID++
+	m.occasions = append(m.occasions, occasion)
+	return occasion, nil
+}
+
+// DeleteOccasion deletes an occasion by ID (mock implementation)
+func (m *MockDB) DeleteOccasion(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := contextError(ctx); err != nil {
+		return err
+	}
+
+	for i, occasion := range m.occasions {
+		if occasion.ID == id {
+			remaining := make([]*models.Occasion, 0, len(m.occasions)-1)
+			remaining = append(remaining, m.occasions[:i]...)
+			m.occasions = append(remaining, m.occasions[i+1:]...)
+			return nil
+		}
+	}
+	return newError(ErrNotFound, "occasion with id %d not found", id)
+}
+
+// ListValues returns every distinct non-empty value of field with its number
+// of quotes, most frequent first
+func (m *MockDB) ListValues(ctx context.Context, field SearchField) ([]models.FacetCount, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := valueColumn(field); err != nil {
+		return nil, err
+	}
+
+	return sortedFacet(m.values[field]), nil
+}
+
+// SuggestValues returns up to limit values of field with a word starting
+// with prefix, matched case-insensitively and Arabic-normalized. Authors are
+// also suggested by their aliases.
+func (m *MockDB) SuggestValues(ctx context.Context, field SearchField, prefix string, limit int) ([]models.FacetCount, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := valueColumn(field); err != nil {
+		return nil, err
+	}
+
+	prefix = normalizeArabic(prefix)
+	matched := make(map[string]bool)
+	for value := range m.values[field] {
+		if hasWordPrefix(value, prefix) {
+			matched[value] = true
+		}
+	}
+	if field == FieldAuthor {
+		for _, author := range m.authors {
+			for _, alias := range author.Aliases {
+				if hasWordPrefix(alias, prefix) && m.values[field][author.Name] > 0 {
+					matched[author.Name] = true
+				}
+			}
+		}
+	}
+
+	values := make([]models.FacetCount, 0, len(matched))
+	for value := range matched {
+		values = append(values, models.FacetCount{Value: value, Count: m.values[field][value]})
+	}
+	return sortSuggestions(values, prefix, limit), nil
+}
+
+// canonicalRequest returns a copy of req whose category is spelled as in
+// the taxonomy or, failing that, as an existing tag, ignoring case. The
+// caller must hold m.mu.
+func (m *MockDB) canonicalRequest(req *models.QuoteRequest) *models.QuoteRequest {
+	canonical := *req
+	if stored, ok := m.tags[strings.ToLower(req.Category)]; ok {
+		canonical.Category = stored
+	}
+	for _, category := range m.categories {
+		if strings.EqualFold(category.Name, req.Category) {
+			canonical.Category = category.Name
+		}
+	}
+	return &canonical
+}
+
+// quoteTags returns the tags of the quote described by req, reusing the
+// spelling of tags seen before like the tags table does. The caller must
+// hold m.mu.
+func (m *MockDB) quoteTags(req *models.QuoteRequest) []string {
+	tags := req.TagNames()
+	for i, tag := range tags {
+		key := strings.ToLower(tag)
+		if stored, ok := m.tags[key]; ok {
+			tags[i] = stored
+		} else {
+			m.tags[key] = tag
+		}
+	}
+	orderTags(tags, req.Category)
+	return tags
+}
+
+// findAuthor returns the author whose name or one of whose aliases is name,
+// ignoring case, preferring an exact name match. The caller must hold m.mu.
+func (m *MockDB) findAuthor(name string) *models.Author {
+	var found *models.Author
+	for _, author := range m.authors {
+		if author.Name == name {
+			return author
+		}
+		if found == nil && strings.EqualFold(author.Name, name) {
+			found = author
+		}
+		if found == nil && hasAlias(author, name) {
+			found = author
+		}
+	}
+	return found
+}
+
+// resolveAuthor returns the canonical name and id of the author a quote
+// naming name belongs to, like the set_quote_author_id trigger: a name or
+// alias matching ignoring case is replaced by the author's stored name and
+// unknown names create a new author. The caller must hold m.mu.
+func (m *MockDB) resolveAuthor(name string) (string, int) {
+	for _, author := range m.authors {
+		if strings.EqualFold(author.Name, name) || hasAlias(author, name) {
+			return author.Name, author.ID
+		}
+	}
+
+	now := time.Now()
+	author := &models.Author{ID: m.nextAuthorID, Name: name, Aliases: []string{}, CreatedAt: now, UpdatedAt: now}
+	m.nextAuthorID++
+	m.authors = append(m.authors, author)
+	return author.Name, author.ID
+}
+
+// hasAlias reports whether name is one of the aliases of author, ignoring case
+func hasAlias(author *models.Author, name string) bool {
+	for _, alias := range author.Aliases {
+		if strings.EqualFold(alias, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueAliases returns aliases sorted, without duplicates and without name,
+// ignoring case, as the author_aliases table stores them
+func uniqueAliases(name string, aliases []string) []string {
+	unique := []string{}
+	seen := map[string]bool{strings.ToLower(name): true}
+	for _, alias := range aliases {
+		if key := strings.ToLower(alias); !seen[key] {
+			seen[key] = true
+			unique = append(unique, alias)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+// withQuoteCount returns a copy of author with its number of quotes. The
+// caller must hold m.mu.
+func (m *MockDB) withQuoteCount(author *models.Author) *models.Author {
+	counted := *author
+	counted.QuoteCount = 0
+	for _, quote := range m.quotes {
+		if quote.AuthorID == author.ID {
+			counted.QuoteCount++
+		}
+	}
+	return &counted
+}
+
+// ListAuthors returns every author with its number of quotes, the authors
+// with most quotes first (mock implementation)
+func (m *MockDB) ListAuthors(ctx context.Context) ([]*models.Author, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+
+	authors := make([]*models.Author, len(m.authors))
+	for i, author := range m.authors {
+		authors[i] = m.withQuoteCount(author)
+	}
+	sortAuthors(authors)
+	return authors, nil
 }
 
-// Count returns the total number of quotes
-func (m *MockDB) Count() (int, error) {
-	return len(m.quotes), nil
+// GetAuthor retrieves an author by ID (mock implementation)
+func (m *MockDB) GetAuthor(ctx context.Context, id int) (*models.Author, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, author := range m.authors {
+		if author.ID == id {
+			return m.withQuoteCount(author), nil
+		}
+	}
+	return nil, newError(ErrNotFound, "author with id %d not found", id)
+}
+
+// UpdateAuthor replaces the details of an author, renaming it on all of its
+// quotes (mock implementation)
+func (m *MockDB) UpdateAuthor(ctx context.Context, id int, req *models.AuthorRequest) (*models.Author, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+
+	index := -1
+	for i, author := range m.authors {
+		if author.ID == id {
+			index = i
+			continue
+		}
+		if strings.EqualFold(author.Name, req.Name) || hasAlias(author, req.Name) {
+			return nil, newError(ErrConflict, "author %q already exists", req.Name)
+		}
+		for _, alias := range req.Aliases {
+			if strings.EqualFold(author.Name, alias) || hasAlias(author, alias) {
+				return nil, newError(ErrConflict, "alias %q already belongs to another author", alias)
+			}
+		}
+	}
+	if index < 0 {
+		return nil, newError(ErrNotFound, "author with id %d not found", id)
+	}
+
+	// Replace rather than mutate so readers holding the old pointer are unaffected
+	updated := *m.authors[index]
+	updated.Name = req.Name
+	updated.NameArabic = req.NameArabic
+	updated.Aliases = uniqueAliases(req.Name, req.Aliases)
+	updated.Kunya = req.Kunya
+	updated.BirthYearHijri = req.BirthYearHijri
+	updated.DeathYearHijri = req.DeathYearHijri
+	updated.BirthYear = req.BirthYear
+	updated.DeathYear = req.DeathYear
+	updated.Bio = req.Bio
+	updated.Era = req.Era
+	updated.UpdatedAt = time.Now()
+	m.authors[index] = &updated
+
+	for i, quote := range m.quotes {
+		if quote.AuthorID == id && quote.Author != req.Name {
+			renamed := *quote
+			renamed.Author = req.Name
+			renamed.UpdatedAt = time.Now()
+			m.quotes[i] = &renamed
+			m.unindex(quote)
+			m.index(&renamed)
+		}
+	}
+
+	return m.withQuoteCount(&updated), nil
+}
+
+// ResolveAuthor retrieves the author whose name or one of whose aliases is
+// name, ignoring case (mock implementation)
+func (m *MockDB) ResolveAuthor(ctx context.Context, name string) (*models.Author, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+
+	if author := m.findAuthor(name); author != nil {
+		return m.withQuoteCount(author), nil
+	}
+	return nil, newError(ErrNotFound, "author %q not found", name)
+}
+
+// MergeAuthors moves all quotes and aliases of the author sourceID to the
+// author targetID and deletes the source, recording its name as an alias of
+// the target (mock implementation)
+func (m *MockDB) MergeAuthors(ctx context.Context, sourceID, targetID int) (*models.Author, error) {
+	if sourceID == targetID {
+		return nil, newError(ErrValidation, "cannot merge author %d into itself", sourceID)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+
+	sourceIndex, targetIndex := -1, -1
+	for i, author := range m.authors {
+		switch author.ID {
+		case sourceID:
+			sourceIndex = i
+		case targetID:
+			targetIndex = i
+		}
+	}
+	if sourceIndex < 0 {
+		return nil, newError(ErrNotFound, "author with id %d not found", sourceID)
+	}
+	if targetIndex < 0 {
+		return nil, newError(ErrNotFound, "author with id %d not found", targetID)
+	}
+	source := m.authors[sourceIndex]
+
+	// Replace rather than mutate so readers holding the old pointer are unaffected
+	merged := *m.authors[targetIndex]
+	merged.Aliases = uniqueAliases(merged.Name, append(append([]string{source.Name}, merged.Aliases...), source.Aliases...))
+	if merged.NameArabic == "" {
+		merged.NameArabic = source.NameArabic
+	}
+	if merged.Kunya == "" {
+		merged.Kunya = source.Kunya
+	}
+	if merged.BirthYearHijri == nil {
+		merged.BirthYearHijri = source.BirthYearHijri
+	}
+	if merged.DeathYearHijri == nil {
+		merged.DeathYearHijri = source.DeathYearHijri
+	}
+	if merged.BirthYear == nil {
+		merged.BirthYear = source.BirthYear
+	}
+	if merged.DeathYear == nil {
+		merged.DeathYear = source.DeathYear
+	}
+	if merged.Bio == "" {
+		merged.Bio = source.Bio
+	}
+	if merged.Era == "" {
+		merged.Era = source.Era
+	}
+	merged.UpdatedAt = time.Now()
+	m.authors[targetIndex] = &merged
+
+	for i, quote := range m.quotes {
+		if quote.AuthorID == sourceID {
+			moved := *quote
+			moved.Author = merged.Name
+			moved.AuthorID = targetID
+			moved.UpdatedAt = time.Now()
+			m.quotes[i] = &moved
+			m.unindex(quote)
+			m.index(&moved)
+		}
+	}
+
+	remaining := make([]*models.Author, 0, len(m.authors)-1)
+	remaining = append(remaining, m.authors[:sourceIndex]...)
+	m.authors = append(remaining, m.authors[sourceIndex+1:]...)
+	return m.withQuoteCount(&merged), nil
+}
+
+// ListCategories returns the category taxonomy as a list of root categories
+// with their descendants (mock implementation)
+func (m *MockDB) ListCategories(ctx context.Context) ([]*models.Category, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+
+	return m.categoryTree(), nil
+}
+
+// ResolveCategory retrieves the category whose slug or name is ref, ignoring
+// case, with its descendants (mock implementation)
+func (m *MockDB) ResolveCategory(ctx context.Context, ref string) (*models.Category, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+
+	if category := findCategory(m.categoryTree(), ref); category != nil {
+		return category, nil
+	}
+	return nil, newError(ErrNotFound, "category %q not found", ref)
+}
+
+// categoryTree builds the category tree from copies of the categories with
+// their quote counts. The caller must hold m.mu.
+func (m *MockDB) categoryTree() []*models.Category {
+	categories := make([]*models.Category, len(m.categories))
+	for i, category := range m.categories {
+		counted := *category
+		for value, count := range m.values[FieldCategory] {
+			if strings.EqualFold(value, category.Name) {
+				counted.QuoteCount += count
+			}
+		}
+		categories[i] = &counted
+	}
+	return buildCategoryTree(categories)
 }
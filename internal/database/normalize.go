@@ -0,0 +1,25 @@
+package database
+
+import (
+	"strings"
+
+	"github.com/albantanie/mahfudzot-generator/internal/arabic"
+)
+
+// normalizeArabic folds s for search. It must agree with normalize_arabic()
+// as redefined in migrations/012_normalize_search_text_nfc.sql, which
+// composes to NFC before folding just like arabic.Fold.
+func normalizeArabic(s string) string {
+	return arabic.Fold(s)
+}
+
+// searchTerms splits a search query into normalized terms
+func searchTerms(query string) []string {
+	return strings.Fields(normalizeArabic(query))
+}
+
+// searchDocument returns the normalized text searched for q, matching the
+// search_text column
+func searchDocument(textArabic, textLatin, translation string) string {
+	return normalizeArabic(textArabic + "\n" + textLatin + "\n" + translation)
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/albantanie/mahfudzot-generator/internal/models"
+)
+
+// OccasionRepository defines the interface for Hijri occasion operations
+type OccasionRepository interface {
+	ListOccasions(ctx context.Context) ([]*models.Occasion, error)
+	CreateOccasion(ctx context.Context, req *models.OccasionRequest) (*models.Occasion, error)
+	DeleteOccasion(ctx context.Context, id int) error
+}
+
+// DefaultOccasions returns the occasions installed by the migration and used
+// by MockDB
+func DefaultOccasions() []*models.OccasionRequest {
+	return []*models.OccasionRequest{
+		{Name: "Islamic New Year", StartMonth: 1, StartDay: 1, EndMonth: 1, EndDay: 10, Category: "Time"},
+		{Name: "Ramadan", StartMonth: 9, StartDay: 1, EndMonth: 9, EndDay: 30, Category: "Patience"},
+		{Name: "First ten days of Dhul Hijjah", StartMonth: 12, StartDay: 1, EndMonth: 12, EndDay: 10, Category: "Spirituality"},
+	}
+}
+
+// ListOccasions retrieves all occasions
+func (db *DB) ListOccasions(ctx context.Context) ([]*models.Occasion, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT id, name, start_month, start_day, end_month, end_day, category, created_at
+		FROM occasions
+		ORDER BY start_month, start_day, id
+	`
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, translateError(err, "occasions")
+	}
+	defer rows.Close()
+
+	occasions := []*models.Occasion{}
+	for rows.Next() {
+		occasion := &models.Occasion{}
+		err := rows.Scan(
+			&occasion.ID,
+			&occasion.Name,
+			&occasion.StartMonth,
+			&occasion.StartDay,
+			&occasion.EndMonth,
+			&occasion.EndDay,
+			&occasion.Category,
+			&occasion.CreatedAt,
+		)
+		if err != nil {
+			return nil, translateError(err, "occasions")
+		}
+		occasions = append(occasions, occasion)
+	}
+
+	return occasions, translateError(rows.Err(), "occasions")
+}
+
+// CreateOccasion creates a new occasion
+func (db *DB) CreateOccasion(ctx context.Context, req *models.OccasionRequest) (*models.Occasion, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	query := `
+		INSERT INTO occasions (name, start_month, start_day, end_month, end_day, category)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, name, start_month, start_day, end_month, end_day, category, created_at
+	`
+
+	occasion := &models.Occasion{}
+	err := db.QueryRowContext(ctx, query,
+		req.Name,
+		req.StartMonth,
+		req.StartDay,
+		req.EndMonth,
+		req.EndDay,
+		req.Category,
+	).Scan(
+		&occasion.ID,
+		&occasion.Name,
+		&occasion.StartMonth,
+		&occasion.StartDay,
+		&occasion.EndMonth,
+		&occasion.EndDay,
+		&occasion.Category,
+		&occasion.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, translateError(err, "occasion")
+	}
+
+	return occasion, nil
+}
+
+// DeleteOccasion deletes an occasion by ID
+func (db *DB) DeleteOccasion(ctx context.Context, id int) error {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := db.ExecContext(ctx, "DELETE FROM occasions WHERE id = $1", id)
+	if err != nil {
+		return translateError(err, fmt.Sprintf("occasion with id %d", id))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return translateError(err, fmt.Sprintf("occasion with id %d", id))
+	}
+
+	if rowsAffected == 0 {
+		return newError(ErrNotFound, "occasion with id %d not found", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,221 @@
+package database
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/albantanie/mahfudzot-generator/internal/models"
+	"github.com/lib/pq"
+)
+
+// randomIDCacheTTL bounds how long quotes written by other processes (the
+// seeder, psql) can go unnoticed by GetRandom. Writes made through DB
+// invalidate the cache immediately.
+const randomIDCacheTTL = time.Minute
+
+// randomAttempts is how often GetRandom retries after picking an id that has
+// been deleted since the cache was loaded
+const randomAttempts = 3
+
+// idCache holds the ids of all quotes so a random quote can be picked
+// uniformly with a single primary key lookup instead of sorting the table
+type idCache struct {
+	mu       sync.Mutex
+	ids      []int
+	loadedAt time.Time
+}
+
+// get returns the cached ids, calling load when they are missing or expired
+func (c *idCache) get(ctx context.Context, load func(context.Context) ([]int, error)) ([]int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ids != nil && time.Since(c.loadedAt) < randomIDCacheTTL {
+		return c.ids, nil
+	}
+
+	ids, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.ids = ids
+	c.loadedAt = time.Now()
+	return ids, nil
+}
+
+// invalidate forces the next get to reload the ids
+func (c *idCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ids = nil
+}
+
+// RandomOptions controls which and how many quotes GetRandom picks
+type RandomOptions struct {
+	// Filter restricts the candidates; its sort, cursor, limit and offset
+	// are ignored
+	Filter QuoteFilter
+	// Count is the number of distinct quotes to return; fewer are returned
+	// when not enough quotes match
+	Count int
+	// Seed makes the selection deterministic: the same seed over the same
+	// candidates always yields the same quotes in the same order
+	Seed int64
+	// Exclude lists ids that must not be picked
+	Exclude []int
+}
+
+// excludeIDs returns the ids not listed in exclude, keeping their order
+func excludeIDs(ids, exclude []int) []int {
+	if len(exclude) == 0 {
+		return ids
+	}
+
+	skip := make(map[int]bool, len(exclude))
+	for _, id := range exclude {
+		skip[id] = true
+	}
+
+	kept := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !skip[id] {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
+
+// pickIDs selects count distinct ids uniformly at random using a partial
+// Fisher-Yates shuffle. Swaps are tracked in a map so ids is never copied or
+// modified and the cost is O(count) regardless of len(ids).
+func pickIDs(ids []int, count int, seed int64) []int {
+	rng := rand.New(rand.NewSource(seed))
+
+	n := len(ids)
+	if count > n {
+		count = n
+	}
+
+	swapped := make(map[int]int, count)
+	position := func(i int) int {
+		if p, ok := swapped[i]; ok {
+			return p
+		}
+		return i
+	}
+
+	picked := make([]int, count)
+	for i := 0; i < count; i++ {
+		j := i + rng.Intn(n-i)
+		picked[i] = ids[position(j)]
+		swapped[j] = position(i)
+	}
+
+	return picked
+}
+
+// GetRandom retrieves distinct random quotes matching opts. Without filter
+// criteria the candidates come from the cached id set, otherwise from an
+// id-only query; either way the quotes are then read by primary key.
+func (db *DB) GetRandom(ctx context.Context, opts RandomOptions) ([]*models.Quote, error) {
+	filter := opts.Filter
+	filter.After = nil
+	where, _ := filter.where()
+
+	for attempt := 0; attempt < randomAttempts; attempt++ {
+		var ids []int
+		var err error
+		if where == "" {
+			ids, err = db.randomIDs.get(ctx, func(ctx context.Context) ([]int, error) {
+				return db.loadIDs(ctx, QuoteFilter{})
+			})
+		} else {
+			ids, err = db.loadIDs(ctx, filter)
+		}
+		if err != nil {
+			return nil, translateError(err, "random quote")
+		}
+		ids = excludeIDs(ids, opts.Exclude)
+		if len(ids) == 0 {
+			return nil, newError(ErrNotFound, "no matching quotes available")
+		}
+
+		picked := pickIDs(ids, opts.Count, opts.Seed)
+		quotes, err := db.getByIDs(ctx, picked)
+		if err != nil {
+			return nil, translateError(err, "random quote")
+		}
+		if len(quotes) == len(picked) {
+			return quotes, nil
+		}
+
+		// Some ids were deleted by another process since they were loaded
+		db.randomIDs.invalidate()
+	}
+
+	return nil, newError(ErrNotFound, "no matching quotes available")
+}
+
+// loadIDs reads the ids of every quote matching filter in ascending order
+func (db *DB) loadIDs(ctx context.Context, filter QuoteFilter) ([]int, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	where, args := filter.where()
+	rows, err := db.QueryContext(ctx, "SELECT id FROM quotes "+where+" ORDER BY id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// getByIDs reads the quotes with the given ids, in the order of ids.
+// Missing ids are skipped.
+func (db *DB) getByIDs(ctx context.Context, ids []int) ([]*models.Quote, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	values := make([]int64, len(ids))
+	for i, id := range ids {
+		values[i] = int64(id)
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT "+quoteColumns+" FROM quotes WHERE id = ANY($1)", pq.Array(values))
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := scanQuotes(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]*models.Quote, len(found))
+	for _, quote := range found {
+		byID[quote.ID] = quote
+	}
+
+	quotes := make([]*models.Quote, 0, len(ids))
+	for _, id := range ids {
+		if quote, ok := byID[id]; ok {
+			quotes = append(quotes, quote)
+		}
+	}
+
+	return quotes, nil
+}
@@ -0,0 +1,165 @@
+package database
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/albantanie/mahfudzot-generator/internal/config"
+)
+
+// drawsPerQuote is how often each quote is expected to be picked by the
+// distribution tests
+const drawsPerQuote = 100
+
+// randomTolerance is how far, as a fraction of the expected count, the
+// number of times any quote is picked may deviate from it
+const randomTolerance = 0.5
+
+// testDB connects to the database configured by the DB_* environment
+// variables, skipping tb when DB_HOST is not set or there are no quotes
+func testDB(tb testing.TB) *DB {
+	tb.Helper()
+
+	if os.Getenv("DB_HOST") == "" {
+		tb.Skip("DB_HOST not set, skipping PostgreSQL test")
+	}
+
+	db, err := New(&config.Load().Database)
+	if err != nil {
+		tb.Fatalf("Failed to connect to database: %v", err)
+	}
+	tb.Cleanup(func() { db.Close() })
+
+	count, err := db.Count(context.Background(), QuoteFilter{})
+	if err != nil {
+		tb.Fatalf("Failed to count quotes: %v", err)
+	}
+	if count == 0 {
+		tb.Skip("the quotes table is empty, run cmd/seeder first")
+	}
+	return db
+}
+
+// checkUniform fails t when any of ids was picked a number of times outside
+// randomTolerance of expected
+func checkUniform(t *testing.T, hits map[int]int, ids []int, expected float64) {
+	t.Helper()
+
+	for _, id := range ids {
+		if math.Abs(float64(hits[id])-expected) > randomTolerance*expected {
+			t.Errorf("id %d picked %d times, want %.0f ± %.0f%%", id, hits[id], expected, randomTolerance*100)
+		}
+		delete(hits, id)
+	}
+	for id := range hits {
+		t.Errorf("picked unknown id %d", id)
+	}
+}
+
+func TestPickIDsUniform(t *testing.T) {
+	ids := make([]int, 50)
+	for i := range ids {
+		ids[i] = 3*i + 1
+	}
+
+	const count = 3
+	draws := drawsPerQuote * len(ids) / count
+	hits := make(map[int]int)
+	for seed := 0; seed < draws; seed++ {
+		picked := pickIDs(ids, count, int64(seed))
+		if len(picked) != count {
+			t.Fatalf("pickIDs returned %d ids, want %d", len(picked), count)
+		}
+		seen := make(map[int]bool)
+		for _, id := range picked {
+			if seen[id] {
+				t.Fatalf("pickIDs(seed %d) = %v picks %d twice", seed, picked, id)
+			}
+			seen[id] = true
+			hits[id]++
+		}
+	}
+
+	checkUniform(t, hits, ids, float64(draws*count)/float64(len(ids)))
+}
+
+func TestRandomDistribution(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping distribution check in short mode")
+	}
+	db := testDB(t)
+	ctx := context.Background()
+
+	ids, err := db.loadIDs(ctx, QuoteFilter{})
+	if err != nil {
+		t.Fatalf("Failed to load quote ids: %v", err)
+	}
+
+	draws := drawsPerQuote * len(ids)
+	hits := make(map[int]int)
+	for seed := 0; seed < draws; seed++ {
+		quotes, err := db.GetRandom(ctx, RandomOptions{Count: 1, Seed: int64(seed)})
+		if err != nil {
+			t.Fatalf("GetRandom: %v", err)
+		}
+		hits[quotes[0].ID]++
+	}
+
+	checkUniform(t, hits, ids, drawsPerQuote)
+}
+
+// BenchmarkRandomOrderByRandom measures the original implementation, which
+// sorts the whole table on every call
+func BenchmarkRandomOrderByRandom(b *testing.B) {
+	db := testDB(b)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		row := db.QueryRowContext(ctx, "SELECT "+quoteColumns+" FROM quotes ORDER BY RANDOM() LIMIT 1")
+		if _, err := scanQuote(row); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkRandomIDRange measures sampling a point in [min(id), max(id)] and
+// taking the next existing id, which is fast but biased towards ids that
+// follow gaps
+func BenchmarkRandomIDRange(b *testing.B) {
+	db := testDB(b)
+	ctx := context.Background()
+
+	query := "SELECT " + quoteColumns + ` FROM quotes,
+		(SELECT MIN(id) AS lo, MAX(id) AS hi FROM quotes) bounds
+		WHERE id >= bounds.lo + FLOOR(RANDOM() * (bounds.hi - bounds.lo + 1))::int
+		ORDER BY id
+		LIMIT 1`
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := scanQuote(db.QueryRowContext(ctx, query)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkRandomCachedIDs measures GetRandom, which picks uniformly from
+// the cached id set and reads the quote by primary key
+func BenchmarkRandomCachedIDs(b *testing.B) {
+	db := testDB(b)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := db.GetRandom(ctx, RandomOptions{Count: 1, Seed: rand.Int63()}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package database
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/albantanie/mahfudzot-generator/internal/arabic"
+	"github.com/albantanie/mahfudzot-generator/internal/models"
+	"github.com/albantanie/mahfudzot-generator/internal/translit"
+)
+
+// BM25 parameters: term frequency saturation and document length
+// normalization
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// corpusStatsTTL bounds how long quotes written by other processes can go
+// unnoticed by the ranking statistics. Writes made through DB invalidate the
+// cache immediately.
+const corpusStatsTTL = time.Minute
+
+// rankTokens returns the tokens BM25 works on: the normalized tokens of s
+// without proclitics, which occur in nearly every quote
+func rankTokens(s string) []string {
+	tokens := arabic.Tokenize(s)
+	kept := tokens[:0]
+	for _, token := range tokens {
+		if !arabic.IsProclitic(token) {
+			kept = append(kept, token)
+		}
+	}
+	return kept
+}
+
+// documentTokens returns the ranking tokens of every text field of q
+func documentTokens(q *models.Quote) []string {
+	return rankTokens(q.TextArabic + "\n" + q.TextLatin + "\n" + q.Translation)
+}
+
+// queryTerms returns the distinct ranking tokens of a search query, in order
+func queryTerms(query string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, token := range rankTokens(query) {
+		if !seen[token] {
+			seen[token] = true
+			terms = append(terms, token)
+		}
+	}
+	return terms
+}
+
+// corpusStats holds the collection statistics BM25 needs: the number of
+// quotes, their total length in tokens and the number of quotes containing
+// each token
+type corpusStats struct {
+	docs   int
+	tokens int
+	df     map[string]int
+}
+
+// newCorpusStats computes the statistics of the given quotes
+func newCorpusStats(quotes []*models.Quote) *corpusStats {
+	stats := &corpusStats{df: make(map[string]int)}
+	for _, quote := range quotes {
+		tokens := documentTokens(quote)
+		stats.docs++
+		stats.tokens += len(tokens)
+
+		seen := make(map[string]bool, len(tokens))
+		for _, token := range tokens {
+			if !seen[token] {
+				seen[token] = true
+				stats.df[token]++
+			}
+		}
+	}
+	return stats
+}
+
+// bm25 scores a document against the distinct query terms
+func (s *corpusStats) bm25(terms, doc []string) float64 {
+	if s.docs == 0 || s.tokens == 0 {
+		return 0
+	}
+
+	tf := make(map[string]int, len(doc))
+	for _, token := range doc {
+		tf[token]++
+	}
+
+	avgLength := float64(s.tokens) / float64(s.docs)
+	lengthNorm := bm25K1 * (1 - bm25B + bm25B*float64(len(doc))/avgLength)
+
+	var score float64
+	for _, term := range terms {
+		f := float64(tf[term])
+		if f == 0 {
+			continue
+		}
+		df := float64(s.df[term])
+		idf := math.Log(1 + (float64(s.docs)-df+0.5)/(df+0.5))
+		score += idf * f * (bm25K1 + 1) / (f + lengthNorm)
+	}
+	return score
+}
+
+// rank scores quotes, which must already match filter and be sorted by
+// sortQuotes, and returns the requested page. With SortRelevance the most
+// relevant quotes come first, ties keeping newest first. A Latin query is
+// scored 1/(1+d) from its transliteration distance d, anything else by BM25
+// of SearchText against stats.
+func (f *QuoteFilter) rank(quotes []*models.Quote, stats *corpusStats) []*models.SearchResult {
+	terms := queryTerms(f.SearchText())
+
+	results := make([]*models.SearchResult, len(quotes))
+	for i, quote := range quotes {
+		var score float64
+		if f.Latin != "" {
+			distance, _ := translit.Match(f.Latin, quote.TextLatin)
+			score = 1 / float64(1+distance)
+		} else {
+			score = stats.bm25(terms, documentTokens(quote))
+		}
+		results[i] = &models.SearchResult{Quote: quote, Score: score}
+	}
+
+	if f.Sort == SortRelevance {
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].Score > results[j].Score
+		})
+	}
+
+	start, end := f.bounds(len(results))
+	return results[start:end]
+}
+
+// statsCache holds the corpus statistics so that ranking does not read
+// every quote on each search
+type statsCache struct {
+	mu       sync.Mutex
+	stats    *corpusStats
+	loadedAt time.Time
+}
+
+// get returns the cached statistics, calling load when they are missing or
+// expired
+func (c *statsCache) get(ctx context.Context, load func(context.Context) (*corpusStats, error)) (*corpusStats, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stats != nil && time.Since(c.loadedAt) < corpusStatsTTL {
+		return c.stats, nil
+	}
+
+	stats, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.stats = stats
+	c.loadedAt = time.Now()
+	return stats, nil
+}
+
+// invalidate forces the next get to reload the statistics
+func (c *statsCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats = nil
+}
+
+// SearchPage is one page of search results with the number of quotes
+// matching the search and their facets, which ignore the cursor, limit and
+// offset of the filter
+type SearchPage struct {
+	Results []*models.SearchResult
+	Total   int
+	Facets  *models.Facets
+}
+
+// searchPage ranks and paginates quotes, which must match filter apart from
+// its cursor and be sorted by sortQuotes, and counts all of them for the
+// total and facets
+func (f *QuoteFilter) searchPage(quotes []*models.Quote, stats *corpusStats) *SearchPage {
+	counts := make(facetCounts)
+	following := make([]*models.Quote, 0, len(quotes))
+	for _, quote := range quotes {
+		counts.addQuote(quote)
+		if f.After == nil || f.After.follows(quote) {
+			following = append(following, quote)
+		}
+	}
+
+	return &SearchPage{
+		Results: f.rank(following, stats),
+		Total:   len(quotes),
+		Facets:  counts.facets(),
+	}
+}
+
+// Search returns a page of the quotes matching filter with their relevance
+// to its text (see SearchText) or Latin search, ordered by filter.Sort and
+// paginated like Find, together with their total and facets. Every matching
+// quote is read once and ranked in Go, which suits a corpus of up to some
+// ten thousand quotes; a larger corpus needs ranking in SQL.
+func (db *DB) Search(ctx context.Context, filter QuoteFilter) (*SearchPage, error) {
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+
+	all := filter
+	all.After = nil
+	quotes, err := db.findAll(ctx, all)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats *corpusStats
+	if filter.Latin == "" {
+		stats, err = db.corpus.get(ctx, db.loadCorpusStats)
+		if err != nil {
+			return nil, translateError(err, "quotes")
+		}
+	}
+
+	return filter.searchPage(quotes, stats), nil
+}
+
+// loadCorpusStats computes the ranking statistics over every quote
+func (db *DB) loadCorpusStats(ctx context.Context) (*corpusStats, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, "SELECT "+quoteColumns+" FROM quotes")
+	if err != nil {
+		return nil, err
+	}
+
+	quotes, err := scanQuotes(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCorpusStats(quotes), nil
+}
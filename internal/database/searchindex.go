@@ -0,0 +1,119 @@
+package database
+
+import (
+	"strings"
+)
+
+// searchIndex is the in-memory counterpart of the search_text column and
+// its trigram index: it keeps each quote's normalized text and a posting
+// list per trigram, so term lookups only verify quotes sharing all of the
+// term's trigrams
+type searchIndex struct {
+	docs  map[int]string
+	grams map[string]map[int]struct{}
+}
+
+// newSearchIndex creates an empty index
+func newSearchIndex() *searchIndex {
+	return &searchIndex{
+		docs:  make(map[int]string),
+		grams: make(map[string]map[int]struct{}),
+	}
+}
+
+// trigrams returns the distinct three-rune substrings of s
+func trigrams(s string) []string {
+	runes := []rune(s)
+	seen := make(map[string]bool)
+	var grams []string
+	for i := 0; i+3 <= len(runes); i++ {
+		gram := string(runes[i : i+3])
+		if !seen[gram] {
+			seen[gram] = true
+			grams = append(grams, gram)
+		}
+	}
+	return grams
+}
+
+// put indexes doc for id, replacing any previous document
+func (idx *searchIndex) put(id int, doc string) {
+	idx.remove(id)
+	idx.docs[id] = doc
+	for _, gram := range trigrams(doc) {
+		ids, ok := idx.grams[gram]
+		if !ok {
+			ids = make(map[int]struct{})
+			idx.grams[gram] = ids
+		}
+		ids[id] = struct{}{}
+	}
+}
+
+// remove drops id from the index
+func (idx *searchIndex) remove(id int) {
+	doc, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+	delete(idx.docs, id)
+	for _, gram := range trigrams(doc) {
+		delete(idx.grams[gram], id)
+		if len(idx.grams[gram]) == 0 {
+			delete(idx.grams, gram)
+		}
+	}
+}
+
+// lookup returns the ids whose document contains every term
+func (idx *searchIndex) lookup(terms []string) map[int]struct{} {
+	var candidates map[int]struct{}
+	for _, term := range terms {
+		for _, gram := range trigrams(term) {
+			candidates = intersect(candidates, idx.grams[gram])
+		}
+	}
+
+	// Terms shorter than three runes have no trigrams: scan everything
+	if candidates == nil {
+		candidates = make(map[int]struct{}, len(idx.docs))
+		for id := range idx.docs {
+			candidates[id] = struct{}{}
+		}
+	}
+
+	matches := make(map[int]struct{}, len(candidates))
+	for id := range candidates {
+		if containsAll(idx.docs[id], terms) {
+			matches[id] = struct{}{}
+		}
+	}
+	return matches
+}
+
+// intersect returns the ids present in both sets; a nil a means "everything"
+func intersect(a, b map[int]struct{}) map[int]struct{} {
+	result := make(map[int]struct{})
+	if a == nil {
+		for id := range b {
+			result[id] = struct{}{}
+		}
+		return result
+	}
+	for id := range a {
+		if _, ok := b[id]; ok {
+			result[id] = struct{}{}
+		}
+	}
+	return result
+}
+
+// containsAll reports whether doc contains every term
+func containsAll(doc string, terms []string) bool {
+	for _, term := range terms {
+		if !strings.Contains(doc, term) {
+			return false
+		}
+	}
+	return true
+}
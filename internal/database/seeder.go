@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -34,6 +35,7 @@ func GetSeedData() []*models.QuoteRequest {
 			Author:      "Prophet Muhammad",
 			Category:    "Patience",
 			Source:      "Hadith",
+			Tags:        []string{"Hope"},
 		},
 		{
 			TextArabic:  "من كان في حاجة أخيه كان الله في حاجته",
@@ -634,15 +636,38 @@ func GetSeedData() []*models.QuoteRequest {
 	}
 }
 
+// GetSeedAliases returns alternative spellings of the seed authors, keyed by
+// the canonical name used in GetSeedData
+func GetSeedAliases() map[string][]string {
+	return map[string][]string{
+		"Prophet Muhammad":      {"Rasulullah", "Nabi Muhammad"},
+		"Imam Ali":              {"Ali ibn Abi Talib", "Ali bin Abi Thalib"},
+		"Imam Al-Ghazali":       {"Al-Ghazali", "Abu Hamid al-Ghazali"},
+		"Imam Ash-Shafi'i":      {"Imam Syafi'i", "Al-Shafi'i"},
+		"Imam Ahmad ibn Hanbal": {"Imam Ahmad", "Ahmad bin Hanbal"},
+		"Imam An-Nawawi":        {"Imam Nawawi", "Al-Nawawi"},
+		"Imam Al-Bukhari":       {"Imam Bukhari", "Al-Bukhari"},
+		"Ibn al-Qayyim":         {"Ibn Qayyim al-Jawziyya", "Ibnul Qayyim"},
+		"Ibn Sina":              {"Avicenna"},
+		"Ibn Rushd":             {"Averroes"},
+		"Rumi":                  {"Jalaluddin Rumi", "Mawlana Rumi"},
+	}
+}
+
 // SeedDatabase populates the database with initial quote data
-func SeedDatabase(db QuoteRepository) error {
+func SeedDatabase(ctx context.Context, db QuoteRepository) error {
 	quotes := GetSeedData()
 
 	log.Printf("Starting to seed database with %d quotes...", len(quotes))
 
 	successCount := 0
 	for i, quote := range quotes {
-		_, err := db.Create(quote)
+		if errs := quote.Validate(); len(errs) > 0 {
+			log.Printf("Skipping invalid quote %d: %v", i+1, errs)
+			continue
+		}
+
+		_, err := db.Create(ctx, quote)
 		if err != nil {
 			log.Printf("Failed to insert quote %d: %v", i+1, err)
 			continue
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/albantanie/mahfudzot-generator/internal/arabic"
+	"golang.org/x/text/unicode/norm"
+)
+
+// TestSeedDataNormalization checks that the Arabic text of every seed quote
+// is stored in NFC and folds consistently, so that search and ranking see
+// the same words as the queries typed against them
+func TestSeedDataNormalization(t *testing.T) {
+	for _, quote := range GetSeedData() {
+		text := quote.TextArabic
+		if got := arabic.NFC(text); got != text {
+			t.Errorf("seed text %q is not in NFC: %q", text, got)
+		}
+
+		folded := arabic.Fold(text)
+		if again := arabic.Fold(folded); again != folded {
+			t.Errorf("Fold is not idempotent on %q: %q then %q", text, folded, again)
+		}
+		if decomposed := arabic.Fold(norm.NFD.String(text)); decomposed != folded {
+			t.Errorf("Fold differs between the NFD and NFC forms of %q: %q and %q", text, decomposed, folded)
+		}
+	}
+}
+
+// TestSeedDataTokenize checks that every word of every seed quote yields
+// exactly one stem, preceded only by proclitics, and that the stem is a
+// suffix of the folded word or of the word with its elided article restored
+func TestSeedDataTokenize(t *testing.T) {
+	for _, quote := range GetSeedData() {
+		for _, word := range strings.Fields(quote.TextArabic) {
+			tokens := arabic.Tokenize(word)
+			if len(tokens) == 0 {
+				if arabic.ContainsArabic(word) {
+					t.Errorf("Tokenize(%q) in %q returned no tokens", word, quote.TextArabic)
+				}
+				continue
+			}
+
+			stem := tokens[len(tokens)-1]
+			for _, tok := range tokens[:len(tokens)-1] {
+				if !arabic.IsProclitic(tok) {
+					t.Errorf("Tokenize(%q) in %q = %q: %q is not a proclitic", word, quote.TextArabic, tokens, tok)
+				}
+			}
+			if len(tokens) > 1 && utf8.RuneCountInString(stem) < 2 {
+				t.Errorf("Tokenize(%q) in %q = %q leaves a stem shorter than two letters", word, quote.TextArabic, tokens)
+			}
+
+			whole := arabic.NormalizeDigits(arabic.Fold(strings.Trim(word, ":.,،؛")))
+			if !strings.HasSuffix(whole, stem) && !strings.HasSuffix("ا"+whole, stem) {
+				t.Errorf("Tokenize(%q) in %q = %q: stem %q is not part of %q", word, quote.TextArabic, tokens, stem, whole)
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// lowerTags returns tags in lower case without duplicates, as compared by
+// the tag filters
+func lowerTags(tags []string) []string {
+	lowered := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		if key := strings.ToLower(strings.TrimSpace(tag)); key != "" && !seen[key] {
+			seen[key] = true
+			lowered = append(lowered, key)
+		}
+	}
+	return lowered
+}
+
+// hasTag reports whether tags contains tag, ignoring case
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAnyTag reports whether tags contains one of wanted, ignoring case
+func hasAnyTag(tags, wanted []string) bool {
+	for _, tag := range wanted {
+		if hasTag(tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// orderTags sorts tags in place as quoteColumns selects them: the primary
+// category first, then by name
+func orderTags(tags []string, category string) {
+	sort.SliceStable(tags, func(i, j int) bool {
+		pi, pj := strings.EqualFold(tags[i], category), strings.EqualFold(tags[j], category)
+		if pi != pj {
+			return pi
+		}
+		return tags[i] < tags[j]
+	})
+}
+
+// setQuoteTags replaces the tags of quote id by names, creating missing
+// tags. Tags are matched ignoring case, keeping the first spelling stored.
+func setQuoteTags(ctx context.Context, tx *sql.Tx, id int, names []string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM quote_tags WHERE quote_id = $1", id); err != nil {
+		return translateError(err, "quote tags")
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, "INSERT INTO tags (name) SELECT UNNEST($1::text[]) ON CONFLICT DO NOTHING", pq.Array(names))
+	if err != nil {
+		return translateError(err, "tags")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO quote_tags (quote_id, tag_id)
+		SELECT $1, id FROM tags WHERE LOWER(name) = ANY($2)
+		ON CONFLICT DO NOTHING`, id, pq.Array(lowerTags(names)))
+	if err != nil {
+		return translateError(err, "quote tags")
+	}
+
+	return nil
+}
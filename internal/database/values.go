@@ -0,0 +1,175 @@
+package database
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/albantanie/mahfudzot-generator/internal/models"
+)
+
+// ValueRepository lists the distinct authors, categories and sources of the
+// quotes with the number of quotes for each
+type ValueRepository interface {
+	ListValues(ctx context.Context, field SearchField) ([]models.FacetCount, error)
+	SuggestValues(ctx context.Context, field SearchField, prefix string, limit int) ([]models.FacetCount, error)
+}
+
+// valueColumns maps the fields that can be listed to their SQL expression.
+// SuggestValues matches normalize_arabic() of these expressions, which
+// migrations/014_add_value_suggestion_indexes.sql indexes; keep them in sync.
+var valueColumns = map[SearchField]string{
+	FieldAuthor:   "author",
+	FieldCategory: "COALESCE(category, '')",
+	FieldSource:   "COALESCE(source, '')",
+}
+
+// valueColumn returns the SQL expression of field, rejecting fields that
+// cannot be listed
+func valueColumn(field SearchField) (string, error) {
+	column, ok := valueColumns[field]
+	if !ok {
+		return "", newError(ErrValidation, "cannot list values of %q", field)
+	}
+	return column, nil
+}
+
+// hasWordPrefix reports whether value, or one of its words, starts with
+// prefix once both are normalized; prefix must already be normalized.
+// Words are separated by spaces or hyphens, so "gh" finds "Imam Al-Ghazali".
+func hasWordPrefix(value, prefix string) bool {
+	value = normalizeArabic(value)
+	return strings.HasPrefix(value, prefix) ||
+		strings.Contains(value, " "+prefix) || strings.Contains(value, "-"+prefix)
+}
+
+// wordPrefixCondition returns the SQL condition matching hasWordPrefix on
+// normalize_arabic(expr), given the patterns of wordPrefixPatterns as $1 to $3
+func wordPrefixCondition(expr string) string {
+	normalized := "normalize_arabic(" + expr + ")"
+	return normalized + " LIKE $1 OR " + normalized + " LIKE $2 OR " + normalized + " LIKE $3"
+}
+
+// wordPrefixPatterns returns the LIKE patterns of wordPrefixCondition for
+// the normalized prefix
+func wordPrefixPatterns(prefix string) []interface{} {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(prefix)
+	return []interface{}{escaped + "%", "% " + escaped + "%", "%-" + escaped + "%"}
+}
+
+// sortSuggestions orders values starting with prefix before those where only
+// a later word does, then by descending count and value, and keeps limit
+func sortSuggestions(values []models.FacetCount, prefix string, limit int) []models.FacetCount {
+	leading := func(v models.FacetCount) bool {
+		return strings.HasPrefix(normalizeArabic(v.Value), prefix)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		a, b := values[i], values[j]
+		if la, lb := leading(a), leading(b); la != lb {
+			return la
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Value < b.Value
+	})
+	if limit > 0 && len(values) > limit {
+		values = values[:limit]
+	}
+	return values
+}
+
+// ListValues returns every distinct non-empty value of field with its number
+// of quotes, most frequent first
+func (db *DB) ListValues(ctx context.Context, field SearchField) ([]models.FacetCount, error) {
+	column, err := valueColumn(field)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := db.distinctValues(ctx, column, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	return sortedFacet(counts), nil
+}
+
+// SuggestValues returns up to limit values of field with a word starting
+// with prefix, matched case-insensitively and Arabic-normalized. Authors are
+// also suggested by their aliases, so "Avicenna" suggests "Ibn Sina".
+func (db *DB) SuggestValues(ctx context.Context, field SearchField, prefix string, limit int) ([]models.FacetCount, error) {
+	column, err := valueColumn(field)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix = normalizeArabic(prefix)
+	cond := wordPrefixCondition(column)
+	if field == FieldAuthor {
+		cond += " OR author_id IN (SELECT author_id FROM author_aliases WHERE " + wordPrefixCondition("alias") + ")"
+	}
+
+	counts, err := db.distinctValues(ctx, column, cond, wordPrefixPatterns(prefix))
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]models.FacetCount, 0, len(counts))
+	for value, count := range counts {
+		values = append(values, models.FacetCount{Value: value, Count: count})
+	}
+	return sortSuggestions(values, prefix, limit), nil
+}
+
+// distinctValues counts the quotes per distinct non-empty value of column,
+// restricted by the optional condition cond
+func (db *DB) distinctValues(ctx context.Context, column, cond string, args []interface{}) (map[string]int, error) {
+	ctx, cancel := db.withTimeout(ctx)
+	defer cancel()
+
+	where := "WHERE " + column + " <> ''"
+	if cond != "" {
+		where += " AND (" + cond + ")"
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT "+column+", COUNT(*) FROM quotes "+where+" GROUP BY 1", args...)
+	if err != nil {
+		return nil, translateError(err, "quotes")
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var value string
+		var count int
+		if err := rows.Scan(&value, &count); err != nil {
+			return nil, translateError(err, "quotes")
+		}
+		counts[value] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err, "quotes")
+	}
+
+	return counts, nil
+}
+
+// valueIndex is the in-memory index of the distinct values of one field: the
+// number of quotes per value
+type valueIndex map[string]int
+
+// add counts a quote with value; empty values are not indexed
+func (idx valueIndex) add(value string) {
+	if value != "" {
+		idx[value]++
+	}
+}
+
+// remove uncounts a quote with value, dropping values no quote has anymore
+func (idx valueIndex) remove(value string) {
+	if idx[value] <= 1 {
+		delete(idx, value)
+		return
+	}
+	idx[value]--
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/albantanie/mahfudzot-generator/internal/auth"
+	"github.com/albantanie/mahfudzot-generator/internal/database"
+)
+
+// AuthMiddleware authenticates API keys and enforces per-route roles
+type AuthMiddleware struct {
+	keys       database.APIKeyRepository
+	publicRead bool
+}
+
+// NewAuthMiddleware creates a new auth middleware
+func NewAuthMiddleware(keys database.APIKeyRepository, publicRead bool) *AuthMiddleware {
+	return &AuthMiddleware{keys: keys, publicRead: publicRead}
+}
+
+// Authenticate resolves the API key sent in the Authorization header, if any,
+// and stores it in the request context. Requests with an unknown or revoked
+// key are rejected; requests without a key continue anonymously.
+func (a *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := bearerToken(r.Header.Get("Authorization"))
+		if token == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key, err := a.keys.GetAPIKeyByHash(r.Context(), auth.HashKey(token))
+		if err != nil && !errors.Is(err, database.ErrNotFound) {
+			sendRepositoryError(w, r, "Failed to verify API key", err)
+			return
+		}
+		if err != nil || key.RevokedAt != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mahfudzot"`)
+			sendErrorResponse(w, r, http.StatusUnauthorized, "Invalid API key", "The API key is unknown or has been revoked")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.WithKey(r.Context(), key)))
+	})
+}
+
+// Require wraps next so that it only runs for keys granting at least role
+func (a *AuthMiddleware) Require(role auth.Role, next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := auth.KeyFromContext(r.Context())
+		if key == nil {
+			if role == auth.RoleReader && a.publicRead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mahfudzot"`)
+			sendErrorResponse(w, r, http.StatusUnauthorized, "Authentication required", "Send an API key in the Authorization header")
+			return
+		}
+
+		if !auth.Role(key.Role).Allows(role) {
+			sendErrorResponse(w, r, http.StatusForbidden, "Insufficient permissions", "This endpoint requires the "+string(role)+" role")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bearerToken extracts the key from "Bearer <key>" or "ApiKey <key>" header values
+func bearerToken(header string) string {
+	scheme, token, found := strings.Cut(strings.TrimSpace(header), " ")
+	if !found {
+		return ""
+	}
+	if !strings.EqualFold(scheme, "Bearer") && !strings.EqualFold(scheme, "ApiKey") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/albantanie/mahfudzot-generator/internal/database"
+	"github.com/albantanie/mahfudzot-generator/internal/models"
+	"github.com/gorilla/mux"
+)
+
+// AuthorHandler handles author-related HTTP requests
+type AuthorHandler struct {
+	authors database.AuthorRepository
+	quotes  database.QuoteRepository
+}
+
+// NewAuthorHandler creates a new author handler
+func NewAuthorHandler(authors database.AuthorRepository, quotes database.QuoteRepository) *AuthorHandler {
+	return &AuthorHandler{authors: authors, quotes: quotes}
+}
+
+// ListAuthors handles GET /api/v1/authors
+func (h *AuthorHandler) ListAuthors(w http.ResponseWriter, r *http.Request) {
+	authors, err := h.authors.ListAuthors(r.Context())
+	if err != nil {
+		sendRepositoryError(w, r, "Failed to retrieve authors", err)
+		return
+	}
+
+	response := models.AuthorsResponse{
+		Success: true,
+		Data:    authors,
+		Total:   len(authors),
+	}
+
+	sendJSONResponse(w, http.StatusOK, response)
+}
+
+// GetAuthor handles GET /api/v1/authors/{id}. The response includes a page
+// of the author's quotes, newest first, selected by limit and page.
+func (h *AuthorHandler) GetAuthor(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		sendErrorResponse(w, r, http.StatusBadRequest, "Invalid author ID", "ID must be a number")
+		return
+	}
+
+	author, err := h.authors.GetAuthor(r.Context(), id)
+	if err != nil {
+		sendRepositoryError(w, r, "Failed to retrieve author", err)
+		return
+	}
+
+	limit, page := parsePagination(r)
+	author.Quotes, err = h.quotes.Find(r.Context(), database.QuoteFilter{
+		AuthorID: id,
+		Sort:     database.SortNewest,
+		Limit:    limit,
+		Offset:   (page - 1) * limit,
+	})
+	if err != nil {
+		sendRepositoryError(w, r, "Failed to retrieve author quotes", err)
+		return
+	}
+
+	response := models.AuthorResponse{
+		Success: true,
+		Data:    author,
+	}
+
+	sendJSONResponse(w, http.StatusOK, response)
+}
+
+// UpdateAuthor handles PUT /api/v1/authors/{id}
+func (h *AuthorHandler) UpdateAuthor(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		sendErrorResponse(w, r, http.StatusBadRequest, "Invalid author ID", "ID must be a number")
+		return
+	}
+
+	var req models.AuthorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendErrorResponse(w, r, http.StatusBadRequest, "Invalid request body", "Request body must be valid JSON")
+		return
+	}
+
+	if errs := req.Validate(); len(errs) > 0 {
+		sendValidationErrors(w, r, errs)
+		return
+	}
+
+	author, err := h.authors.UpdateAuthor(r.Context(), id, &req)
+	if err != nil {
+		sendRepositoryError(w, r, "Failed to update author", err)
+		return
+	}
+
+	response := models.AuthorResponse{
+		Success: true,
+		Message: "Author updated successfully",
+		Data:    author,
+	}
+
+	sendJSONResponse(w, http.StatusOK, response)
+}
+
+// MergeAuthor handles POST /api/v1/authors/{id}/merge. All quotes and aliases
+// of the author are moved to the target author, the author is deleted and
+// its name becomes an alias of the target.
+func (h *AuthorHandler) MergeAuthor(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		sendErrorResponse(w, r, http.StatusBadRequest, "Invalid author ID", "ID must be a number")
+		return
+	}
+
+	var req models.MergeAuthorsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendErrorResponse(w, r, http.StatusBadRequest, "Invalid request body", "Request body must be valid JSON")
+		return
+	}
+
+	if errs := req.Validate(); len(errs) > 0 {
+		sendValidationErrors(w, r, errs)
+		return
+	}
+
+	author, err := h.authors.MergeAuthors(r.Context(), id, req.TargetID)
+	if err != nil {
+		sendRepositoryError(w, r, "Failed to merge authors", err)
+		return
+	}
+
+	response := models.AuthorResponse{
+		Success: true,
+		Message: "Authors merged successfully",
+		Data:    author,
+	}
+
+	sendJSONResponse(w, http.StatusOK, response)
+}
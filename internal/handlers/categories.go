@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/albantanie/mahfudzot-generator/internal/database"
+	"github.com/albantanie/mahfudzot-generator/internal/models"
+	"github.com/gorilla/mux"
+)
+
+// CategoryHandler handles requests for the category taxonomy
+type CategoryHandler struct {
+	db database.CategoryRepository
+}
+
+// NewCategoryHandler creates a new category handler
+func NewCategoryHandler(db database.CategoryRepository) *CategoryHandler {
+	return &CategoryHandler{db: db}
+}
+
+// GetCategoryTree handles GET /api/v1/categories/tree
+func (h *CategoryHandler) GetCategoryTree(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.db.ListCategories(r.Context())
+	if err != nil {
+		sendRepositoryError(w, r, "Failed to retrieve categories", err)
+		return
+	}
+
+	response := models.CategoriesResponse{
+		Success: true,
+		Data:    categories,
+	}
+
+	sendJSONResponse(w, http.StatusOK, response)
+}
+
+// GetCategory handles GET /api/v1/categories/{slug}, which also accepts the
+// category name, and returns the category with its descendants
+func (h *CategoryHandler) GetCategory(w http.ResponseWriter, r *http.Request) {
+	category, err := h.db.ResolveCategory(r.Context(), mux.Vars(r)["slug"])
+	if err != nil {
+		sendRepositoryError(w, r, "Failed to retrieve category", err)
+		return
+	}
+
+	response := models.CategoryResponse{
+		Success: true,
+		Data:    category,
+	}
+
+	sendJSONResponse(w, http.StatusOK, response)
+}
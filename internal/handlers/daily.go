@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/albantanie/mahfudzot-generator/internal/database"
+	"github.com/albantanie/mahfudzot-generator/internal/hijri"
+	"github.com/albantanie/mahfudzot-generator/internal/models"
+	"github.com/gorilla/mux"
+)
+
+// defaultDailyTimezone is used when the tz parameter is absent
+const defaultDailyTimezone = "UTC"
+
+// pastDailyMaxAge is the cache lifetime of daily quotes for explicit dates
+const pastDailyMaxAge = 24 * time.Hour
+
+// DailyHandler handles the quote of the day and the Hijri occasions that
+// theme it
+type DailyHandler struct {
+	quotes      database.QuoteRepository
+	occasions   database.OccasionRepository
+	hijriOffset int
+}
+
+// NewDailyHandler creates a new daily quote handler. hijriOffset shifts the
+// computed Hijri date by that many days to follow local moon sighting.
+func NewDailyHandler(quotes database.QuoteRepository, occasions database.OccasionRepository, hijriOffset int) *DailyHandler {
+	return &DailyHandler{quotes: quotes, occasions: occasions, hijriOffset: hijriOffset}
+}
+
+// GetDailyQuote handles GET /api/v1/quotes/daily and
+// GET /api/v1/quotes/daily/{date}. The quote is picked deterministically from
+// the date and the category, so every server returns the same one. Without
+// an explicit category, the category of the Hijri occasion covering the date
+// is preferred when it has quotes.
+func (h *DailyHandler) GetDailyQuote(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var errs models.ValidationErrors
+
+	tzName := query.Get("tz")
+	if tzName == "" {
+		tzName = defaultDailyTimezone
+	}
+	loc, err := time.LoadLocation(tzName)
+	if err != nil {
+		errs = append(errs, models.FieldError{Field: "tz", Message: "must be an IANA time zone such as Asia/Jakarta"})
+		loc = time.UTC
+	}
+
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	date := today
+	if value, ok := mux.Vars(r)["date"]; ok {
+		date, err = time.ParseInLocation(dateLayout, value, loc)
+		if err != nil {
+			errs = append(errs, models.FieldError{Field: "date", Message: "must be a valid date (YYYY-MM-DD)"})
+		}
+	}
+
+	hijriDate := hijri.FromGregorian(date, h.hijriOffset)
+	if err == nil && !hijriDate.Valid() {
+		errs = append(errs, models.FieldError{Field: "date", Message: "must not precede 1 Muharram 1 AH (622-07-19)"})
+	}
+
+	if len(errs) > 0 {
+		sendInvalidQuery(w, r, errs)
+		return
+	}
+	category := query.Get("category")
+
+	var occasion *models.Occasion
+	if category == "" {
+		occasion, err = h.occasionFor(r, hijriDate)
+		if err != nil {
+			sendRepositoryError(w, r, "Failed to retrieve occasions", err)
+			return
+		}
+	}
+
+	var quote *models.Quote
+	if occasion != nil {
+		quote, err = h.pick(r, date, occasion.Category)
+		if errors.Is(err, database.ErrNotFound) {
+			// No quotes in the occasion's category, use the whole corpus
+			occasion = nil
+		} else if err != nil {
+			sendRepositoryError(w, r, "Failed to retrieve daily quote", err)
+			return
+		}
+	}
+	if occasion == nil {
+		quote, err = h.pick(r, date, category)
+		if err != nil {
+			sendRepositoryError(w, r, "Failed to retrieve daily quote", err)
+			return
+		}
+	}
+
+	// Today's quote changes at local midnight, other dates only when the
+	// corpus does
+	expires := now.Add(pastDailyMaxAge)
+	if date.Equal(today) {
+		expires = today.AddDate(0, 0, 1)
+	}
+	maxAge := int(expires.Sub(now).Seconds())
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(maxAge))
+	w.Header().Set("Expires", expires.UTC().Format(http.TimeFormat))
+
+	response := models.DailyQuoteResponse{
+		Success:  true,
+		Date:     date.Format(dateLayout),
+		Timezone: loc.String(),
+		Hijri: &models.HijriDate{
+			Year:            hijriDate.Year,
+			Month:           hijriDate.Month,
+			Day:             hijriDate.Day,
+			MonthName:       hijriDate.MonthName(),
+			MonthNameArabic: hijriDate.MonthNameArabic(),
+			Formatted:       hijriDate.String(),
+		},
+		Data: quote,
+	}
+	if occasion != nil {
+		response.Occasion = occasion.Name
+	}
+
+	sendJSONResponse(w, http.StatusOK, response)
+}
+
+// pick selects the quote of date among the quotes whose category is
+// category, ignoring case, or among all quotes when category is empty
+func (h *DailyHandler) pick(r *http.Request, date time.Time, category string) (*models.Quote, error) {
+	opts := database.RandomOptions{
+		Count: 1,
+		Seed:  dailySeed(date, category),
+	}
+	if category != "" {
+		opts.Filter.Categories = []string{category}
+	}
+
+	quotes, err := h.quotes.GetRandom(r.Context(), opts)
+	if err != nil {
+		return nil, err
+	}
+	return quotes[0], nil
+}
+
+// occasionFor returns the narrowest occasion covering d, or nil
+func (h *DailyHandler) occasionFor(r *http.Request, d hijri.Date) (*models.Occasion, error) {
+	occasions, err := h.occasions.ListOccasions(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	var best *models.Occasion
+	for _, occasion := range occasions {
+		if occasion.Includes(d.Month, d.Day) && (best == nil || occasion.Length() < best.Length()) {
+			best = occasion
+		}
+	}
+	return best, nil
+}
+
+// GetOccasions handles GET /api/v1/occasions
+func (h *DailyHandler) GetOccasions(w http.ResponseWriter, r *http.Request) {
+	occasions, err := h.occasions.ListOccasions(r.Context())
+	if err != nil {
+		sendRepositoryError(w, r, "Failed to retrieve occasions", err)
+		return
+	}
+
+	response := models.OccasionsResponse{
+		Success: true,
+		Data:    occasions,
+	}
+
+	sendJSONResponse(w, http.StatusOK, response)
+}
+
+// CreateOccasion handles POST /api/v1/occasions
+func (h *DailyHandler) CreateOccasion(w http.ResponseWriter, r *http.Request) {
+	var req models.OccasionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendErrorResponse(w, r, http.StatusBadRequest, "Invalid request body", "Request body must be valid JSON")
+		return
+	}
+
+	if errs := req.Validate(); len(errs) > 0 {
+		sendValidationErrors(w, r, errs)
+		return
+	}
+
+	occasion, err := h.occasions.CreateOccasion(r.Context(), &req)
+	if err != nil {
+		sendRepositoryError(w, r, "Failed to create occasion", err)
+		return
+	}
+
+	response := models.OccasionResponse{
+		Success: true,
+		Message: "Occasion created successfully",
+		Data:    occasion,
+	}
+
+	sendJSONResponse(w, http.StatusCreated, response)
+}
+
+// DeleteOccasion handles DELETE /api/v1/occasions/{id}
+func (h *DailyHandler) DeleteOccasion(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		sendErrorResponse(w, r, http.StatusBadRequest, "Invalid occasion ID", "ID must be a number")
+		return
+	}
+
+	if err := h.occasions.DeleteOccasion(r.Context(), id); err != nil {
+		sendRepositoryError(w, r, "Failed to delete occasion", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// dailySeed derives the selection seed from the calendar date and category,
+// independent of time zone offsets and server state
+func dailySeed(date time.Time, category string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte("daily:" + date.Format(dateLayout) + ":" + strings.ToLower(category)))
+	return int64(hash.Sum64())
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/albantanie/mahfudzot-generator/internal/database"
+)
+
+// statusForError maps a repository error kind to an HTTP status code
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, database.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, database.ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, database.ErrUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// sendRepositoryError sends the response for an error returned by the
+// repository layer. Client-safe messages from *database.Error are passed
+// through; anything else is logged and replaced by a generic description.
+func sendRepositoryError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	// The client has gone away, there is nobody to respond to
+	if errors.Is(err, context.Canceled) && r.Context().Err() != nil {
+		return
+	}
+
+	status := statusForError(err)
+
+	detail := "An internal error occurred"
+	var repoErr *database.Error
+	if errors.As(err, &repoErr) {
+		detail = repoErr.Message
+	}
+
+	if status >= http.StatusInternalServerError {
+		log.Printf("%s %s: %s: %v", r.Method, r.URL.Path, message, err)
+	}
+
+	sendErrorResponse(w, r, status, message, detail)
+}
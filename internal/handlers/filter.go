@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/albantanie/mahfudzot-generator/internal/database"
+	"github.com/albantanie/mahfudzot-generator/internal/models"
+)
+
+// Pagination defaults and bounds shared by every listing endpoint
+const (
+	defaultLimit = 10
+	maxLimit     = 100
+)
+
+// maxRandomCount bounds the count parameter of the random endpoint
+const maxRandomCount = 50
+
+// dateLayout is accepted for created_from/created_to besides RFC 3339
+const dateLayout = "2006-01-02"
+
+// parsePagination reads limit and page, falling back to the defaults for
+// missing or out-of-range values
+func parsePagination(r *http.Request) (limit, page int) {
+	limit = defaultLimit
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= maxLimit {
+		limit = l
+	}
+
+	page = 1
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		page = p
+	}
+
+	return limit, page
+}
+
+// parseQuoteFilter builds a repository filter from the criteria parameters
+// (see parseCriteria) plus sort, limit, page and cursor. Invalid values are
+// reported together as field errors. The returned page is zero when the
+// request uses cursor pagination, which is selected by the presence of
+// cursor (empty for the first page). defaultSort applies when sort is
+// absent; relevance is only accepted where it is the default, i.e. for
+// searches.
+func parseQuoteFilter(r *http.Request, defaultSort database.SortOrder) (database.QuoteFilter, int, models.ValidationErrors) {
+	query := r.URL.Query()
+	filter, errs := parseCriteria(query)
+
+	limit, page := parsePagination(r)
+	filter.Limit = limit
+	filter.Offset = (page - 1) * limit
+
+	filter.Sort = defaultSort
+	if value := query.Get("sort"); value != "" {
+		sortOrder, err := database.ParseSortOrder(value)
+		if err != nil || (sortOrder == database.SortRelevance && defaultSort != database.SortRelevance) {
+			message := "must be newest or oldest"
+			if defaultSort == database.SortRelevance {
+				message = "must be relevance, newest or oldest"
+			}
+			errs = append(errs, models.FieldError{Field: "sort", Message: message})
+		}
+		filter.Sort = sortOrder
+	}
+
+	if query.Has("cursor") {
+		page = 0
+		filter.Offset = 0
+		if filter.Sort == database.SortRelevance {
+			errs = append(errs, models.FieldError{Field: "cursor", Message: "is not supported when sorting by relevance"})
+		} else if value := query.Get("cursor"); value != "" {
+			cursor, err := database.DecodeCursor(value)
+			switch {
+			case err != nil:
+				errs = append(errs, models.FieldError{Field: "cursor", Message: "is not a valid cursor"})
+			case cursor.Sort != filter.Sort:
+				errs = append(errs, models.FieldError{Field: "cursor", Message: "was issued for a different sort order"})
+			default:
+				filter.After = cursor
+			}
+		}
+	}
+
+	return filter, page, errs
+}
+
+// parseRandomOptions builds the options of the random endpoint from the
+// criteria parameters plus count and seed. Without a seed a fresh one is
+// drawn so that the caller can report it for replaying the selection.
+func parseRandomOptions(r *http.Request) (database.RandomOptions, models.ValidationErrors) {
+	query := r.URL.Query()
+	filter, errs := parseCriteria(query)
+	opts := database.RandomOptions{Filter: filter, Count: 1, Seed: rand.Int63()}
+
+	if value := query.Get("count"); value != "" {
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 1 || count > maxRandomCount {
+			errs = append(errs, models.FieldError{Field: "count", Message: "must be a number between 1 and " + strconv.Itoa(maxRandomCount)})
+		}
+		opts.Count = count
+	}
+
+	if value := query.Get("seed"); value != "" {
+		seed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			errs = append(errs, models.FieldError{Field: "seed", Message: "must be a 64-bit integer"})
+		}
+		opts.Seed = seed
+	}
+
+	return opts, errs
+}
+
+// parseCriteria reads the filter criteria author, category, source, text,
+// tags, tag_match, max_length, created_from and created_to
+func parseCriteria(query url.Values) (database.QuoteFilter, models.ValidationErrors) {
+	var errs models.ValidationErrors
+
+	filter := database.QuoteFilter{
+		Author:   query.Get("author"),
+		Category: query.Get("category"),
+		Source:   query.Get("source"),
+		Text:     query.Get("text"),
+	}
+
+	if value := query.Get("tags"); value != "" {
+		var tags []string
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+		switch query.Get("tag_match") {
+		case "", "any":
+			filter.AnyTags = tags
+		case "all":
+			filter.AllTags = tags
+		default:
+			errs = append(errs, models.FieldError{Field: "tag_match", Message: "must be any or all"})
+		}
+	}
+
+	if value := query.Get("max_length"); value != "" {
+		maxLength, err := strconv.Atoi(value)
+		if err != nil || maxLength < 1 {
+			errs = append(errs, models.FieldError{Field: "max_length", Message: "must be a positive number"})
+		}
+		filter.MaxLength = maxLength
+	}
+
+	if value := query.Get("created_from"); value != "" {
+		from, _, err := parseTime(value)
+		if err != nil {
+			errs = append(errs, models.FieldError{Field: "created_from", Message: "must be a date (YYYY-MM-DD) or RFC 3339 timestamp"})
+		}
+		filter.CreatedFrom = from
+	}
+
+	if value := query.Get("created_to"); value != "" {
+		to, dateOnly, err := parseTime(value)
+		if err != nil {
+			errs = append(errs, models.FieldError{Field: "created_to", Message: "must be a date (YYYY-MM-DD) or RFC 3339 timestamp"})
+		}
+		// A bare date includes the whole day
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.CreatedTo = to
+	}
+
+	return filter, errs
+}
+
+// parseTime accepts an RFC 3339 timestamp or a date, reporting which it was
+func parseTime(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(dateLayout, value); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, false, err
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"html"
+	"strings"
+
+	"github.com/albantanie/mahfudzot-generator/internal/arabic"
+	"github.com/albantanie/mahfudzot-generator/internal/database"
+	"github.com/albantanie/mahfudzot-generator/internal/models"
+	"github.com/albantanie/mahfudzot-generator/internal/translit"
+)
+
+// Markup wrapped around matched words in search highlights
+const (
+	highlightStart = "<mark>"
+	highlightEnd   = "</mark>"
+)
+
+// snippetWords bounds the length of a highlight; longer fields are cut to a
+// window around their first match
+const snippetWords = 24
+
+// highlightResult returns the fields of q that match the search of filter,
+// keyed by their JSON name, with the matching words marked. Arabic-aware
+// searches mark every word containing a normalized query term, fuzzy
+// searches the words of text_latin close to a query word.
+func highlightResult(q *models.Quote, filter database.QuoteFilter) map[string]string {
+	highlights := make(map[string]string)
+
+	if filter.Latin != "" {
+		words := strings.Fields(filter.Latin)
+		match := func(word string) bool {
+			for _, w := range words {
+				if _, ok := translit.Match(w, word); ok {
+					return true
+				}
+			}
+			return false
+		}
+		if snippet, ok := highlight(q.TextLatin, match); ok {
+			highlights["text_latin"] = snippet
+		}
+	} else {
+		terms := strings.Fields(arabic.Fold(filter.SearchText()))
+		match := func(word string) bool {
+			folded := arabic.Fold(word)
+			for _, term := range terms {
+				if strings.Contains(folded, term) {
+					return true
+				}
+			}
+			return false
+		}
+		fields := []struct{ name, text string }{
+			{"text_arabic", q.TextArabic},
+			{"text_latin", q.TextLatin},
+			{"translation", q.Translation},
+		}
+		for _, field := range fields {
+			if snippet, ok := highlight(field.text, match); ok {
+				highlights[field.name] = snippet
+			}
+		}
+	}
+
+	if len(highlights) == 0 {
+		return nil
+	}
+	return highlights
+}
+
+// highlight HTML-escapes text and marks the words for which match is true,
+// reporting whether there were any. Texts longer than snippetWords are cut
+// around the first match, with ellipses where words were dropped.
+func highlight(text string, match func(word string) bool) (string, bool) {
+	words := strings.Fields(text)
+
+	first := -1
+	marked := make([]bool, len(words))
+	for i, word := range words {
+		if match(word) {
+			marked[i] = true
+			if first < 0 {
+				first = i
+			}
+		}
+	}
+	if first < 0 {
+		return "", false
+	}
+
+	start, end := 0, len(words)
+	if len(words) > snippetWords {
+		start = max(0, min(first-snippetWords/4, len(words)-snippetWords))
+		end = start + snippetWords
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("… ")
+	}
+	for i := start; i < end; i++ {
+		if i > start {
+			b.WriteByte(' ')
+		}
+		if marked[i] {
+			b.WriteString(highlightStart + html.EscapeString(words[i]) + highlightEnd)
+		} else {
+			b.WriteString(html.EscapeString(words[i]))
+		}
+	}
+	if end < len(words) {
+		b.WriteString(" …")
+	}
+	return b.String(), true
+}
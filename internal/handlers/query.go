@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/albantanie/mahfudzot-generator/internal/database"
+)
+
+// searchFields maps the qualifiers accepted in search queries to fields
+var searchFields = map[string]database.SearchField{
+	"text":     database.FieldText,
+	"author":   database.FieldAuthor,
+	"category": database.FieldCategory,
+	"source":   database.FieldSource,
+}
+
+// queryError points at the part of a search query that could not be parsed
+type queryError struct {
+	pos     int // 1-based, in characters
+	token   string
+	message string
+}
+
+// Error implements the error interface
+func (e *queryError) Error() string {
+	return fmt.Sprintf("%s at position %d: %s", e.message, e.pos, e.token)
+}
+
+// queryParser reads the search query language:
+//
+//	knowledge light         both words must occur (AND is implicit)
+//	"light upon light"      a quoted phrase
+//	author:"Imam Ali"       a field qualifier: text, author, category, source
+//	-dunya                  negation
+//	patience OR sabr        either term; OR binds tighter than AND
+//
+// Unqualified terms search the text fields like a plain query.
+type queryParser struct {
+	input []rune
+	pos   int
+}
+
+// parseSearchQuery compiles a search query into filter clauses, one per
+// AND-ed group of OR-ed terms
+func parseSearchQuery(input string) ([]database.SearchClause, error) {
+	p := &queryParser{input: []rune(input)}
+
+	var clauses []database.SearchClause
+	pendingOr := -1
+	for {
+		p.skipSpace()
+		if p.done() {
+			break
+		}
+
+		if p.atOr() {
+			if len(clauses) == 0 || pendingOr >= 0 {
+				return nil, p.errorAt(p.pos, "OR must be between two terms")
+			}
+			pendingOr = p.pos
+			p.pos += 2
+			continue
+		}
+
+		term, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+
+		if pendingOr >= 0 {
+			last := len(clauses) - 1
+			clauses[last] = append(clauses[last], term)
+			pendingOr = -1
+		} else {
+			clauses = append(clauses, database.SearchClause{term})
+		}
+	}
+
+	if pendingOr >= 0 {
+		return nil, p.errorAt(pendingOr, "OR must be between two terms")
+	}
+	if len(clauses) == 0 {
+		return nil, &queryError{pos: 1, token: input, message: "query has no terms"}
+	}
+	return clauses, nil
+}
+
+// term reads an optionally negated and qualified word or phrase
+func (p *queryParser) term() (database.SearchTerm, error) {
+	term := database.SearchTerm{Field: database.FieldText}
+	start := p.pos
+
+	if p.input[p.pos] == '-' {
+		term.Negate = true
+		p.pos++
+		if p.done() || unicode.IsSpace(p.input[p.pos]) {
+			return term, p.errorAt(start, "missing term after -")
+		}
+	}
+
+	if name, ok := p.qualifier(); ok {
+		field, known := searchFields[strings.ToLower(name)]
+		if !known {
+			return term, p.errorAt(p.pos, fmt.Sprintf("unknown field %q (expected text, author, category or source)", name))
+		}
+		term.Field = field
+		p.pos += len([]rune(name)) + 1
+		if p.done() || unicode.IsSpace(p.input[p.pos]) {
+			return term, p.errorAt(start, fmt.Sprintf("missing value after %s:", name))
+		}
+	}
+
+	if p.input[p.pos] == '"' {
+		value, err := p.phrase()
+		if err != nil {
+			return term, err
+		}
+		term.Value = value
+		return term, nil
+	}
+
+	term.Value = p.word()
+	return term, nil
+}
+
+// qualifier returns the field name when the input continues with ASCII
+// letters followed by a colon
+func (p *queryParser) qualifier() (string, bool) {
+	end := p.pos
+	for end < len(p.input) && p.input[end] < unicode.MaxASCII && unicode.IsLetter(p.input[end]) {
+		end++
+	}
+	if end == p.pos || end >= len(p.input) || p.input[end] != ':' {
+		return "", false
+	}
+	return string(p.input[p.pos:end]), true
+}
+
+// phrase reads a double-quoted phrase, collapsing inner whitespace
+func (p *queryParser) phrase() (string, error) {
+	start := p.pos
+	end := start + 1
+	for end < len(p.input) && p.input[end] != '"' {
+		end++
+	}
+	if end >= len(p.input) {
+		return "", p.errorAt(start, "unterminated quoted phrase")
+	}
+
+	value := strings.Join(strings.Fields(string(p.input[start+1:end])), " ")
+	if value == "" {
+		return "", p.errorAt(start, "empty quoted phrase")
+	}
+	p.pos = end + 1
+	return value, nil
+}
+
+// word reads up to the next whitespace
+func (p *queryParser) word() string {
+	start := p.pos
+	for !p.done() && !unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+	return string(p.input[start:p.pos])
+}
+
+// atOr reports whether the input continues with the OR operator
+func (p *queryParser) atOr() bool {
+	end := p.pos + 2
+	return end <= len(p.input) && string(p.input[p.pos:end]) == "OR" &&
+		(end == len(p.input) || unicode.IsSpace(p.input[end]))
+}
+
+func (p *queryParser) skipSpace() {
+	for !p.done() && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *queryParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+// errorAt reports message for the whitespace-delimited token at pos
+func (p *queryParser) errorAt(pos int, message string) *queryError {
+	end := pos
+	for end < len(p.input) && !unicode.IsSpace(p.input[end]) {
+		end++
+	}
+	return &queryError{pos: pos + 1, token: string(p.input[pos:end]), message: message}
+}
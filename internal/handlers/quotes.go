@@ -2,83 +2,162 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/albantanie/mahfudzot-generator/internal/database"
 	"github.com/albantanie/mahfudzot-generator/internal/models"
+	"github.com/albantanie/mahfudzot-generator/internal/shuffle"
 	"github.com/gorilla/mux"
 )
 
 // QuoteHandler handles quote-related HTTP requests
 type QuoteHandler struct {
-	db database.QuoteRepository
+	db         database.QuoteRepository
+	authors    database.AuthorRepository
+	categories database.CategoryRepository
+	shuffles   *shuffle.Store
 }
 
 // NewQuoteHandler creates a new quote handler
-func NewQuoteHandler(db database.QuoteRepository) *QuoteHandler {
-	return &QuoteHandler{db: db}
+func NewQuoteHandler(db database.QuoteRepository, authors database.AuthorRepository, categories database.CategoryRepository, shuffles *shuffle.Store) *QuoteHandler {
+	return &QuoteHandler{db: db, authors: authors, categories: categories, shuffles: shuffles}
 }
 
 // GetQuotes handles GET /api/v1/quotes
 func (h *QuoteHandler) GetQuotes(w http.ResponseWriter, r *http.Request) {
-	// Parse query parameters
-	limitStr := r.URL.Query().Get("limit")
-	pageStr := r.URL.Query().Get("page")
-
-	limit := 10 // default
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-			limit = l
-		}
+	filter, page, errs := parseQuoteFilter(r, database.SortNewest)
+	if len(errs) > 0 {
+		sendInvalidQuery(w, r, errs)
+		return
+	}
+
+	h.listQuotes(w, r, filter, page, "Failed to retrieve quotes")
+}
+
+// SearchQuotes handles GET /api/v1/quotes/search?q=. q uses the query
+// language of parseSearchQuery; Arabic text is matched regardless of
+// diacritics and letter variants, and the listing parameters of GetQuotes
+// can be combined with it. With fuzzy=true, q is instead matched against
+// text_latin tolerating transliteration variants. Results are sorted
+// by relevance unless sort says otherwise, and carry their score and
+// highlighted matches.
+func (h *QuoteHandler) SearchQuotes(w http.ResponseWriter, r *http.Request) {
+	filter, page, errs := parseQuoteFilter(r, database.SortRelevance)
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		errs = append(errs, models.FieldError{Field: "q", Message: "is required"})
 	}
 
-	page := 1 // default
-	if pageStr != "" {
-		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
-			page = p
+	fuzzy := false
+	if value := r.URL.Query().Get("fuzzy"); value != "" {
+		var err error
+		if fuzzy, err = strconv.ParseBool(value); err != nil {
+			errs = append(errs, models.FieldError{Field: "fuzzy", Message: "must be true or false"})
 		}
 	}
 
-	offset := (page - 1) * limit
+	switch {
+	case fuzzy:
+		filter.Latin = q
+	case q != "":
+		clauses, err := parseSearchQuery(q)
+		if err != nil {
+			errs = append(errs, models.FieldError{Field: "q", Message: err.Error()})
+		}
+		filter.Clauses = clauses
+	}
 
-	quotes, err := h.db.GetAll(limit, offset)
-	if err != nil {
-		h.sendErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve quotes", err.Error())
+	if len(errs) > 0 {
+		sendInvalidQuery(w, r, errs)
 		return
 	}
 
-	total, err := h.db.Count()
+	// Fetch one extra result to learn whether another page follows
+	fetch := filter
+	fetch.Limit = filter.Limit + 1
+
+	found, err := h.db.Search(r.Context(), fetch)
 	if err != nil {
-		h.sendErrorResponse(w, http.StatusInternalServerError, "Failed to count quotes", err.Error())
+		sendRepositoryError(w, r, "Failed to search quotes", err)
 		return
 	}
 
-	response := models.QuotesResponse{
-		Success: true,
-		Data:    quotes,
-		Total:   total,
-		Page:    page,
-		Limit:   limit,
+	results := found.Results
+	hasNext := len(results) > filter.Limit
+	if hasNext {
+		results = results[:filter.Limit]
+	}
+
+	for _, result := range results {
+		result.Highlights = highlightResult(result.Quote, filter)
+	}
+
+	response := models.SearchResponse{
+		Success:    true,
+		Data:       results,
+		Total:      found.Total,
+		Page:       page,
+		Limit:      filter.Limit,
+		TotalPages: (found.Total + filter.Limit - 1) / filter.Limit,
+		HasNext:    hasNext,
+		HasPrev:    page > 1 || filter.After != nil,
+		Facets:     found.Facets,
 	}
 
-	h.sendJSONResponse(w, http.StatusOK, response)
+	if hasNext && filter.Sort != database.SortRelevance {
+		response.NextCursor = database.NewCursor(filter.Sort, results[len(results)-1].Quote).Encode()
+	}
+
+	sendJSONResponse(w, http.StatusOK, response)
 }
 
-// GetRandomQuote handles GET /api/v1/quotes/random
+// GetRandomQuote handles GET /api/v1/quotes/random. Without count a single
+// quote is returned; with count a list of distinct quotes. The seed used is
+// sent in the X-Random-Seed header so the selection can be replayed. In
+// shuffle mode (see randomInShuffle) quotes do not repeat per client.
 func (h *QuoteHandler) GetRandomQuote(w http.ResponseWriter, r *http.Request) {
-	quote, err := h.db.GetRandom()
+	opts, errs := parseRandomOptions(r)
+	if len(errs) > 0 {
+		sendInvalidQuery(w, r, errs)
+		return
+	}
+
+	var quotes []*models.Quote
+	var err error
+	if token, ok := shuffleRequested(r); ok {
+		quotes, err = h.randomInShuffle(w, r, token, opts)
+	} else {
+		quotes, err = h.db.GetRandom(r.Context(), opts)
+	}
 	if err != nil {
-		h.sendErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve random quote", err.Error())
+		sendRepositoryError(w, r, "Failed to retrieve random quote", err)
 		return
 	}
 
-	response := models.QuoteResponse{
-		Success: true,
-		Data:    quote,
+	w.Header().Set("X-Random-Seed", strconv.FormatInt(opts.Seed, 10))
+
+	if !r.URL.Query().Has("count") {
+		response := models.QuoteResponse{
+			Success: true,
+			Data:    quotes[0],
+		}
+
+		sendJSONResponse(w, http.StatusOK, response)
+		return
+	}
+
+	response := models.QuotesResponse{
+		Success:    true,
+		Data:       quotes,
+		Total:      len(quotes),
+		Limit:      opts.Count,
+		TotalPages: 1,
 	}
 
-	h.sendJSONResponse(w, http.StatusOK, response)
+	sendJSONResponse(w, http.StatusOK, response)
 }
 
 // GetQuoteByID handles GET /api/v1/quotes/{id}
@@ -88,13 +167,13 @@ func (h *QuoteHandler) GetQuoteByID(w http.ResponseWriter, r *http.Request) {
 
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		h.sendErrorResponse(w, http.StatusBadRequest, "Invalid quote ID", "ID must be a number")
+		sendErrorResponse(w, r, http.StatusBadRequest, "Invalid quote ID", "ID must be a number")
 		return
 	}
 
-	quote, err := h.db.GetByID(id)
+	quote, err := h.db.GetByID(r.Context(), id)
 	if err != nil {
-		h.sendErrorResponse(w, http.StatusNotFound, "Quote not found", err.Error())
+		sendRepositoryError(w, r, "Failed to retrieve quote", err)
 		return
 	}
 
@@ -103,127 +182,254 @@ func (h *QuoteHandler) GetQuoteByID(w http.ResponseWriter, r *http.Request) {
 		Data:    quote,
 	}
 
-	h.sendJSONResponse(w, http.StatusOK, response)
+	sendJSONResponse(w, http.StatusOK, response)
 }
 
-// HealthCheck handles GET /health
-func (h *QuoteHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
-	response := map[string]interface{}{
-		"status":  "healthy",
-		"service": "mahfudzot-generator",
+// CreateQuote handles POST /api/v1/quotes
+func (h *QuoteHandler) CreateQuote(w http.ResponseWriter, r *http.Request) {
+	var req models.QuoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendErrorResponse(w, r, http.StatusBadRequest, "Invalid request body", "Request body must be valid JSON")
+		return
 	}
 
-	h.sendJSONResponse(w, http.StatusOK, response)
-}
+	if errs := req.Validate(); len(errs) > 0 {
+		sendValidationErrors(w, r, errs)
+		return
+	}
+
+	quote, err := h.db.Create(r.Context(), &req)
+	if err != nil {
+		sendRepositoryError(w, r, "Failed to create quote", err)
+		return
+	}
+
+	response := models.QuoteResponse{
+		Success: true,
+		Message: "Quote created successfully",
+		Data:    quote,
+	}
 
-// sendJSONResponse sends a JSON response
-func (h *QuoteHandler) sendJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(data)
+	w.Header().Set("Location", "/api/v1/quotes/"+strconv.Itoa(quote.ID))
+	sendJSONResponse(w, http.StatusCreated, response)
 }
 
-// GetQuotesByAuthor handles GET /api/v1/quotes/author/{author}
-func (h *QuoteHandler) GetQuotesByAuthor(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	author := vars["author"]
+// UpdateQuote handles PUT /api/v1/quotes/{id}
+func (h *QuoteHandler) UpdateQuote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		sendErrorResponse(w, r, http.StatusBadRequest, "Invalid quote ID", "ID must be a number")
+		return
+	}
 
-	if author == "" {
-		h.sendErrorResponse(w, http.StatusBadRequest, "Author parameter is required", "Missing author parameter")
+	var req models.QuoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendErrorResponse(w, r, http.StatusBadRequest, "Invalid request body", "Request body must be valid JSON")
 		return
 	}
 
-	// Parse query parameters
-	limitStr := r.URL.Query().Get("limit")
-	pageStr := r.URL.Query().Get("page")
+	h.saveQuote(w, r, id, &req)
+}
 
-	limit := 10 // default
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-			limit = l
-		}
+// PatchQuote handles PATCH /api/v1/quotes/{id}
+func (h *QuoteHandler) PatchQuote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		sendErrorResponse(w, r, http.StatusBadRequest, "Invalid quote ID", "ID must be a number")
+		return
 	}
 
-	page := 1 // default
-	if pageStr != "" {
-		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
-			page = p
-		}
+	existing, err := h.db.GetByID(r.Context(), id)
+	if err != nil {
+		sendRepositoryError(w, r, "Failed to retrieve quote", err)
+		return
 	}
 
-	offset := (page - 1) * limit
+	// Start from the stored quote so that fields absent from the body are kept
+	req := models.QuoteRequest{
+		TextArabic:  existing.TextArabic,
+		TextLatin:   existing.TextLatin,
+		Translation: existing.Translation,
+		Author:      existing.Author,
+		Category:    existing.Category,
+		Source:      existing.Source,
+		Tags:        existing.SecondaryTags(),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendErrorResponse(w, r, http.StatusBadRequest, "Invalid request body", "Request body must be valid JSON")
+		return
+	}
+
+	h.saveQuote(w, r, id, &req)
+}
+
+// saveQuote validates req and stores it as the new content of quote id
+func (h *QuoteHandler) saveQuote(w http.ResponseWriter, r *http.Request, id int, req *models.QuoteRequest) {
+	if errs := req.Validate(); len(errs) > 0 {
+		sendValidationErrors(w, r, errs)
+		return
+	}
 
-	quotes, err := h.db.GetByAuthor(author, limit, offset)
+	quote, err := h.db.Update(r.Context(), id, req)
 	if err != nil {
-		h.sendErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve quotes by author", err.Error())
+		sendRepositoryError(w, r, "Failed to update quote", err)
 		return
 	}
 
-	response := models.QuotesResponse{
+	response := models.QuoteResponse{
 		Success: true,
-		Data:    quotes,
-		Total:   len(quotes), // Note: This is not the total count, just current page count
-		Page:    page,
-		Limit:   limit,
+		Message: "Quote updated successfully",
+		Data:    quote,
+	}
+
+	sendJSONResponse(w, http.StatusOK, response)
+}
+
+// DeleteQuote handles DELETE /api/v1/quotes/{id}
+func (h *QuoteHandler) DeleteQuote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		sendErrorResponse(w, r, http.StatusBadRequest, "Invalid quote ID", "ID must be a number")
+		return
+	}
+
+	if err := h.db.Delete(r.Context(), id); err != nil {
+		sendRepositoryError(w, r, "Failed to delete quote", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HealthCheck handles GET /health
+func (h *QuoteHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
+	response := map[string]interface{}{
+		"status":  "healthy",
+		"service": "mahfudzot-generator",
+	}
+
+	sendJSONResponse(w, http.StatusOK, response)
+}
+
+// GetQuotesByAuthor handles GET /api/v1/quotes/author/{author}. A name or
+// alias of a known author lists all of that author's quotes; any other value
+// matches part of the author name as before.
+func (h *QuoteHandler) GetQuotesByAuthor(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	author := vars["author"]
+
+	if author == "" {
+		sendErrorResponse(w, r, http.StatusBadRequest, "Author parameter is required", "Missing author parameter")
+		return
+	}
+
+	filter, page, errs := parseQuoteFilter(r, database.SortNewest)
+	if len(errs) > 0 {
+		sendInvalidQuery(w, r, errs)
+		return
+	}
+	resolved, err := h.authors.ResolveAuthor(r.Context(), author)
+	switch {
+	case err == nil:
+		filter.AuthorID = resolved.ID
+	case errors.Is(err, database.ErrNotFound):
+		filter.Author = author
+	default:
+		sendRepositoryError(w, r, "Failed to retrieve quotes by author", err)
+		return
 	}
 
-	h.sendJSONResponse(w, http.StatusOK, response)
+	h.listQuotes(w, r, filter, page, "Failed to retrieve quotes by author")
 }
 
-// GetQuotesByCategory handles GET /api/v1/quotes/category/{category}
+// GetQuotesByCategory handles GET /api/v1/quotes/category/{category}. The
+// slug or name of a taxonomy category lists the quotes having that category
+// as their category or one of their tags, and with descendants=true also
+// those of all categories below it; any other value matches part of the
+// category as before.
 func (h *QuoteHandler) GetQuotesByCategory(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	category := vars["category"]
 
 	if category == "" {
-		h.sendErrorResponse(w, http.StatusBadRequest, "Category parameter is required", "Missing category parameter")
+		sendErrorResponse(w, r, http.StatusBadRequest, "Category parameter is required", "Missing category parameter")
 		return
 	}
 
-	// Parse query parameters
-	limitStr := r.URL.Query().Get("limit")
-	pageStr := r.URL.Query().Get("page")
-
-	limit := 10 // default
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-			limit = l
+	filter, page, errs := parseQuoteFilter(r, database.SortNewest)
+	descendants := false
+	if value := r.URL.Query().Get("descendants"); value != "" {
+		var err error
+		if descendants, err = strconv.ParseBool(value); err != nil {
+			errs = append(errs, models.FieldError{Field: "descendants", Message: "must be true or false"})
 		}
 	}
+	if len(errs) > 0 {
+		sendInvalidQuery(w, r, errs)
+		return
+	}
 
-	page := 1 // default
-	if pageStr != "" {
-		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
-			page = p
-		}
+	resolved, err := h.categories.ResolveCategory(r.Context(), category)
+	switch {
+	case err == nil && descendants:
+		filter.InCategories = resolved.Names()
+	case err == nil:
+		filter.InCategories = []string{resolved.Name}
+	case errors.Is(err, database.ErrNotFound) && !descendants:
+		filter.Category = category
+	default:
+		sendRepositoryError(w, r, "Failed to retrieve quotes by category", err)
+		return
 	}
 
-	offset := (page - 1) * limit
+	h.listQuotes(w, r, filter, page, "Failed to retrieve quotes by category")
+}
+
+// listQuotes sends one page of the quotes matching filter together with the
+// total number of matches. A zero page selects cursor pagination.
+func (h *QuoteHandler) listQuotes(w http.ResponseWriter, r *http.Request, filter database.QuoteFilter, page int, message string) {
+	// Fetch one extra quote to learn whether another page follows
+	fetch := filter
+	fetch.Limit = filter.Limit + 1
 
-	quotes, err := h.db.GetByCategory(category, limit, offset)
+	quotes, err := h.db.Find(r.Context(), fetch)
 	if err != nil {
-		h.sendErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve quotes by category", err.Error())
+		sendRepositoryError(w, r, message, err)
 		return
 	}
 
-	response := models.QuotesResponse{
-		Success: true,
-		Data:    quotes,
-		Total:   len(quotes), // Note: This is not the total count, just current page count
-		Page:    page,
-		Limit:   limit,
+	hasNext := len(quotes) > filter.Limit
+	if hasNext {
+		quotes = quotes[:filter.Limit]
 	}
 
-	h.sendJSONResponse(w, http.StatusOK, response)
-}
+	total, err := h.db.Count(r.Context(), filter)
+	if err != nil {
+		sendRepositoryError(w, r, "Failed to count quotes", err)
+		return
+	}
+
+	facets, err := h.db.Facets(r.Context(), filter)
+	if err != nil {
+		sendRepositoryError(w, r, "Failed to count facets", err)
+		return
+	}
+
+	response := models.QuotesResponse{
+		Success:    true,
+		Data:       quotes,
+		Total:      total,
+		Page:       page,
+		Limit:      filter.Limit,
+		TotalPages: (total + filter.Limit - 1) / filter.Limit,
+		HasNext:    hasNext,
+		HasPrev:    page > 1 || filter.After != nil,
+		Facets:     facets,
+	}
 
-// sendErrorResponse sends an error response
-func (h *QuoteHandler) sendErrorResponse(w http.ResponseWriter, statusCode int, message, error string) {
-	response := models.ErrorResponse{
-		Success: false,
-		Error:   error,
-		Message: message,
+	if hasNext {
+		response.NextCursor = database.NewCursor(filter.Sort, quotes[len(quotes)-1]).Encode()
 	}
 
-	h.sendJSONResponse(w, statusCode, response)
+	sendJSONResponse(w, http.StatusOK, response)
 }
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/albantanie/mahfudzot-generator/internal/models"
+)
+
+// problemContentType is the media type of RFC 7807 error responses
+const problemContentType = "application/problem+json"
+
+// sendJSONResponse sends a JSON response
+func sendJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
+	writeJSON(w, statusCode, "application/json", data)
+}
+
+// writeJSON encodes data as the response body with the given content type
+func writeJSON(w http.ResponseWriter, statusCode int, contentType string, data interface{}) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+// sendErrorResponse sends an error response. message is a short summary of
+// what failed and detail explains why. Clients that accept
+// application/problem+json get an RFC 7807 document, everyone else the
+// legacy envelope.
+func sendErrorResponse(w http.ResponseWriter, r *http.Request, statusCode int, message, detail string) {
+	sendErrors(w, r, statusCode, message, detail, nil)
+}
+
+// sendErrors is sendErrorResponse with optional field-level errors
+func sendErrors(w http.ResponseWriter, r *http.Request, statusCode int, message, detail string, fieldErrors []models.FieldError) {
+	w.Header().Add("Vary", "Accept")
+
+	if acceptsProblemJSON(r) {
+		problem := models.ProblemDetails{
+			Type:     "about:blank",
+			Title:    http.StatusText(statusCode),
+			Status:   statusCode,
+			Detail:   detail,
+			Instance: r.URL.RequestURI(),
+			Errors:   fieldErrors,
+		}
+		writeJSON(w, statusCode, problemContentType, problem)
+		return
+	}
+
+	response := models.ErrorResponse{
+		Success: false,
+		Error:   detail,
+		Message: message,
+		Errors:  fieldErrors,
+	}
+
+	sendJSONResponse(w, statusCode, response)
+}
+
+// sendValidationErrors sends a 422 response listing every invalid field
+func sendValidationErrors(w http.ResponseWriter, r *http.Request, errs models.ValidationErrors) {
+	sendErrors(w, r, http.StatusUnprocessableEntity, "Validation failed", "The request contains invalid fields", errs)
+}
+
+// sendInvalidQuery sends a 400 response listing every invalid query parameter
+func sendInvalidQuery(w http.ResponseWriter, r *http.Request, errs models.ValidationErrors) {
+	sendErrors(w, r, http.StatusBadRequest, "Invalid query parameters", "One or more query parameters are invalid", errs)
+}
+
+// acceptsProblemJSON reports whether the Accept header explicitly lists
+// application/problem+json with a non-zero quality
+func acceptsProblemJSON(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || mediaType != problemContentType {
+			continue
+		}
+		if q, ok := params["q"]; ok {
+			if quality, err := strconv.ParseFloat(q, 64); err != nil || quality <= 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
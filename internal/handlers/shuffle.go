@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/albantanie/mahfudzot-generator/internal/database"
+	"github.com/albantanie/mahfudzot-generator/internal/models"
+)
+
+// Shuffle mode token transport
+const (
+	shuffleHeader      = "X-Shuffle-Token"
+	shuffleCycleHeader = "X-Shuffle-Cycle"
+	shuffleCookie      = "mahfudzot_shuffle"
+)
+
+// shuffleRequested reports whether the request uses shuffle mode, which is
+// enabled by shuffle=true or by sending a token in the X-Shuffle-Token
+// header or the shuffle cookie. The token is empty when none was sent.
+func shuffleRequested(r *http.Request) (string, bool) {
+	if token := r.Header.Get(shuffleHeader); token != "" {
+		return token, true
+	}
+	if cookie, err := r.Cookie(shuffleCookie); err == nil && cookie.Value != "" {
+		return cookie.Value, true
+	}
+	enabled, _ := strconv.ParseBool(r.URL.Query().Get("shuffle"))
+	return "", enabled
+}
+
+// shuffleAttempts bounds how often randomInShuffle picks again after a
+// concurrent request with the same token reserved one of the picked quotes
+const shuffleAttempts = 5
+
+// randomInShuffle picks random quotes the client identified by token has not
+// been served in its current cycle. Once every candidate has been served a
+// new cycle starts. Unknown or expired tokens are replaced by a new one,
+// which is sent back in the X-Shuffle-Token header and the shuffle cookie.
+func (h *QuoteHandler) randomInShuffle(w http.ResponseWriter, r *http.Request, token string, opts database.RandomOptions) ([]*models.Quote, error) {
+	for attempt := 0; attempt < shuffleAttempts; attempt++ {
+		served, cycle, ok := h.shuffles.Served(token)
+		if !ok {
+			var err error
+			if token, err = h.shuffles.NewToken(); err != nil {
+				return nil, err
+			}
+			served, cycle = nil, 1
+		}
+
+		opts.Exclude = served
+		quotes, err := h.db.GetRandom(r.Context(), opts)
+		if errors.Is(err, database.ErrNotFound) && len(served) > 0 {
+			// Everything has been served, start over
+			h.shuffles.Restart(token, cycle)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		ids := make([]int, len(quotes))
+		for i, quote := range quotes {
+			ids[i] = quote.ID
+		}
+		if !h.shuffles.Reserve(token, cycle, ids...) {
+			// A concurrent request was served some of them meanwhile
+			continue
+		}
+
+		w.Header().Set(shuffleHeader, token)
+		w.Header().Set(shuffleCycleHeader, strconv.Itoa(cycle))
+		http.SetCookie(w, &http.Cookie{
+			Name:     shuffleCookie,
+			Value:    token,
+			Path:     "/api/v1/quotes/random",
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+
+		return quotes, nil
+	}
+
+	return nil, &database.Error{Kind: database.ErrConflict, Message: "too many concurrent requests with this shuffle token"}
+}
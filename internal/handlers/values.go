@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/albantanie/mahfudzot-generator/internal/database"
+	"github.com/albantanie/mahfudzot-generator/internal/models"
+)
+
+// Bounds of the limit parameter of the suggest endpoints
+const (
+	defaultSuggestLimit = 10
+	maxSuggestLimit     = 50
+)
+
+// ValueHandler lists the categories and sources used by quotes, for filter
+// pickers, and suggests authors, categories and sources for typeahead
+type ValueHandler struct {
+	db database.ValueRepository
+}
+
+// NewValueHandler creates a new value handler
+func NewValueHandler(db database.ValueRepository) *ValueHandler {
+	return &ValueHandler{db: db}
+}
+
+// GetCategories handles GET /api/v1/categories
+func (h *ValueHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
+	h.listValues(w, r, database.FieldCategory, "Failed to retrieve categories")
+}
+
+// GetSources handles GET /api/v1/sources
+func (h *ValueHandler) GetSources(w http.ResponseWriter, r *http.Request) {
+	h.listValues(w, r, database.FieldSource, "Failed to retrieve sources")
+}
+
+// SuggestAuthors handles GET /api/v1/authors/suggest?prefix=
+func (h *ValueHandler) SuggestAuthors(w http.ResponseWriter, r *http.Request) {
+	h.suggestValues(w, r, database.FieldAuthor, "Failed to suggest authors")
+}
+
+// SuggestCategories handles GET /api/v1/categories/suggest?prefix=
+func (h *ValueHandler) SuggestCategories(w http.ResponseWriter, r *http.Request) {
+	h.suggestValues(w, r, database.FieldCategory, "Failed to suggest categories")
+}
+
+// SuggestSources handles GET /api/v1/sources/suggest?prefix=
+func (h *ValueHandler) SuggestSources(w http.ResponseWriter, r *http.Request) {
+	h.suggestValues(w, r, database.FieldSource, "Failed to suggest sources")
+}
+
+// listValues sends every value of field with its number of quotes
+func (h *ValueHandler) listValues(w http.ResponseWriter, r *http.Request, field database.SearchField, message string) {
+	values, err := h.db.ListValues(r.Context(), field)
+	if err != nil {
+		sendRepositoryError(w, r, message, err)
+		return
+	}
+
+	sendJSONResponse(w, http.StatusOK, models.ValuesResponse{
+		Success: true,
+		Data:    values,
+		Total:   len(values),
+	})
+}
+
+// suggestValues sends the values of field having a word that starts with the
+// prefix parameter, values starting with it first. Matching ignores case and
+// Arabic diacritics and letter variants.
+func (h *ValueHandler) suggestValues(w http.ResponseWriter, r *http.Request, field database.SearchField, message string) {
+	query := r.URL.Query()
+	var errs models.ValidationErrors
+
+	prefix := strings.TrimSpace(query.Get("prefix"))
+	if prefix == "" {
+		errs = append(errs, models.FieldError{Field: "prefix", Message: "is required"})
+	}
+
+	limit := defaultSuggestLimit
+	if value := query.Get("limit"); value != "" {
+		l, err := strconv.Atoi(value)
+		if err != nil || l < 1 || l > maxSuggestLimit {
+			errs = append(errs, models.FieldError{Field: "limit", Message: "must be a number between 1 and " + strconv.Itoa(maxSuggestLimit)})
+		}
+		limit = l
+	}
+
+	if len(errs) > 0 {
+		sendInvalidQuery(w, r, errs)
+		return
+	}
+
+	values, err := h.db.SuggestValues(r.Context(), field, prefix, limit)
+	if err != nil {
+		sendRepositoryError(w, r, message, err)
+		return
+	}
+
+	sendJSONResponse(w, http.StatusOK, models.ValuesResponse{
+		Success: true,
+		Data:    values,
+		Total:   len(values),
+	})
+}
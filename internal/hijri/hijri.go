@@ -0,0 +1,130 @@
+// Package hijri converts between the Gregorian and Islamic (Hijri) calendars
+// using the tabular (arithmetical) Islamic calendar. The conversion is
+// entirely offline; it can differ from sighting-based or Umm al-Qura dates by
+// a day or two, which callers can compensate for with a day offset.
+package hijri
+
+import (
+	"fmt"
+	"time"
+)
+
+// epoch is the Julian Day Number of 1 Muharram 1 AH (16 July 622 Julian)
+const epoch = 1948440
+
+// Date is a day in the Hijri calendar
+type Date struct {
+	Year  int
+	Month int
+	Day   int
+}
+
+var monthNames = [12]string{
+	"Muharram", "Safar", "Rabi al-Awwal", "Rabi al-Thani",
+	"Jumada al-Ula", "Jumada al-Akhirah", "Rajab", "Shaban",
+	"Ramadan", "Shawwal", "Dhul Qadah", "Dhul Hijjah",
+}
+
+var monthNamesArabic = [12]string{
+	"محرم", "صفر", "ربيع الأول", "ربيع الآخر",
+	"جمادى الأولى", "جمادى الآخرة", "رجب", "شعبان",
+	"رمضان", "شوال", "ذو القعدة", "ذو الحجة",
+}
+
+// FromGregorian returns the Hijri date of the calendar day of t in t's
+// location, shifted by offsetDays
+func FromGregorian(t time.Time, offsetDays int) Date {
+	return fromJDN(gregorianToJDN(t.Year(), int(t.Month()), t.Day()) + offsetDays)
+}
+
+// ToGregorian returns midnight UTC of the Gregorian day matching d, shifted
+// back by offsetDays so that it inverts FromGregorian
+func ToGregorian(d Date, offsetDays int) time.Time {
+	year, month, day := jdnToGregorian(d.jdn() - offsetDays)
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// IsLeapYear reports whether year has 355 days (Dhul Hijjah has 30)
+func IsLeapYear(year int) bool {
+	return mod(14+11*year, 30) < 11
+}
+
+// DaysInMonth returns the number of days of month in year
+func DaysInMonth(year, month int) int {
+	if month%2 == 1 || (month == 12 && IsLeapYear(year)) {
+		return 30
+	}
+	return 29
+}
+
+// Valid reports whether d is an existing Hijri date
+func (d Date) Valid() bool {
+	return d.Year >= 1 && d.Month >= 1 && d.Month <= 12 && d.Day >= 1 && d.Day <= DaysInMonth(d.Year, d.Month)
+}
+
+// MonthName returns the transliterated name of d's month
+func (d Date) MonthName() string {
+	return monthNames[d.Month-1]
+}
+
+// MonthNameArabic returns the Arabic name of d's month
+func (d Date) MonthNameArabic() string {
+	return monthNamesArabic[d.Month-1]
+}
+
+// String formats d as "9 Ramadan 1446 AH"
+func (d Date) String() string {
+	return fmt.Sprintf("%d %s %d AH", d.Day, d.MonthName(), d.Year)
+}
+
+// jdn returns the Julian Day Number of d
+func (d Date) jdn() int {
+	return d.Day + (59*(d.Month-1)+1)/2 + (d.Year-1)*354 + floorDiv(3+11*d.Year, 30) + epoch - 1
+}
+
+// fromJDN returns the Hijri date of a Julian Day Number
+func fromJDN(jdn int) Date {
+	year := floorDiv(30*(jdn-epoch)+10646, 10631)
+	month := (11*(jdn-Date{Year: year, Month: 1, Day: 1}.jdn()) + 330) / 325
+	if month > 12 {
+		month = 12
+	}
+	day := jdn - Date{Year: year, Month: month, Day: 1}.jdn() + 1
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// gregorianToJDN returns the Julian Day Number of a proleptic Gregorian date
+func gregorianToJDN(year, month, day int) int {
+	a := (14 - month) / 12
+	y := year + 4800 - a
+	m := month + 12*a - 3
+	return day + (153*m+2)/5 + 365*y + y/4 - y/100 + y/400 - 32045
+}
+
+// jdnToGregorian returns the proleptic Gregorian date of a Julian Day Number
+func jdnToGregorian(jdn int) (year, month, day int) {
+	a := jdn + 32044
+	b := (4*a + 3) / 146097
+	c := a - 146097*b/4
+	d := (4*c + 3) / 1461
+	e := c - 1461*d/4
+	m := (5*e + 2) / 153
+	day = e - (153*m+2)/5 + 1
+	month = m + 3 - 12*(m/10)
+	year = 100*b + d - 4800 + m/10
+	return year, month, day
+}
+
+// floorDiv divides rounding towards negative infinity
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// mod returns the non-negative remainder of a divided by b
+func mod(a, b int) int {
+	return ((a % b) + b) % b
+}
@@ -0,0 +1,180 @@
+package hijri_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/albantanie/mahfudzot-generator/internal/hijri"
+)
+
+// day returns midnight UTC of a Gregorian date
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+// knownDates pairs Gregorian days with their tabular Hijri dates
+var knownDates = []struct {
+	gregorian time.Time
+	hijri     hijri.Date
+}{
+	{day(622, time.July, 19), hijri.Date{Year: 1, Month: 1, Day: 1}},
+	{day(1970, time.January, 1), hijri.Date{Year: 1389, Month: 10, Day: 22}},
+	{day(2000, time.January, 1), hijri.Date{Year: 1420, Month: 9, Day: 24}},
+	{day(2023, time.March, 23), hijri.Date{Year: 1444, Month: 9, Day: 1}},
+	{day(2024, time.July, 7), hijri.Date{Year: 1445, Month: 12, Day: 30}},
+	{day(2024, time.July, 8), hijri.Date{Year: 1446, Month: 1, Day: 1}},
+	{day(2025, time.March, 1), hijri.Date{Year: 1446, Month: 9, Day: 1}},
+	{day(2025, time.March, 30), hijri.Date{Year: 1446, Month: 9, Day: 30}},
+	{day(2025, time.June, 26), hijri.Date{Year: 1446, Month: 12, Day: 29}},
+	{day(2026, time.February, 25), hijri.Date{Year: 1447, Month: 9, Day: 8}},
+}
+
+func TestFromGregorian(t *testing.T) {
+	for _, tt := range knownDates {
+		if got := hijri.FromGregorian(tt.gregorian, 0); got != tt.hijri {
+			t.Errorf("FromGregorian(%s) = %v, want %v", tt.gregorian.Format("2006-01-02"), got, tt.hijri)
+		}
+	}
+}
+
+func TestFromGregorianUsesLocalDay(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	local := time.Date(2026, time.February, 25, 1, 0, 0, 0, jakarta)
+
+	if got, want := hijri.FromGregorian(local, 0), (hijri.Date{Year: 1447, Month: 9, Day: 8}); got != want {
+		t.Errorf("FromGregorian(%s) = %v, want %v", local, got, want)
+	}
+	if got, want := hijri.FromGregorian(local.UTC(), 0), (hijri.Date{Year: 1447, Month: 9, Day: 7}); got != want {
+		t.Errorf("FromGregorian(%s) = %v, want %v", local.UTC(), got, want)
+	}
+}
+
+func TestToGregorian(t *testing.T) {
+	for _, tt := range knownDates {
+		if got := hijri.ToGregorian(tt.hijri, 0); !got.Equal(tt.gregorian) {
+			t.Errorf("ToGregorian(%v) = %s, want %s", tt.hijri, got.Format("2006-01-02"), tt.gregorian.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, offset := range []int{-2, -1, 0, 1, 2} {
+		prev := hijri.FromGregorian(day(1899, time.December, 31), offset)
+		for d := day(1900, time.January, 1); d.Year() <= 2100; d = d.AddDate(0, 0, 1) {
+			h := hijri.FromGregorian(d, offset)
+			if !h.Valid() {
+				t.Fatalf("FromGregorian(%s, %d) = invalid %v", d.Format("2006-01-02"), offset, h)
+			}
+			if back := hijri.ToGregorian(h, offset); !back.Equal(d) {
+				t.Fatalf("ToGregorian(FromGregorian(%s, %d)) = %s", d.Format("2006-01-02"), offset, back.Format("2006-01-02"))
+			}
+
+			// Consecutive days advance by exactly one Hijri day
+			next := hijri.Date{Year: prev.Year, Month: prev.Month, Day: prev.Day + 1}
+			if next.Day > hijri.DaysInMonth(prev.Year, prev.Month) {
+				next.Month, next.Day = prev.Month+1, 1
+				if next.Month > 12 {
+					next.Year, next.Month = prev.Year+1, 1
+				}
+			}
+			if h != next {
+				t.Fatalf("FromGregorian(%s, %d) = %v follows %v", d.Format("2006-01-02"), offset, h, prev)
+			}
+			prev = h
+		}
+	}
+}
+
+func TestLeapYearDhulHijjah(t *testing.T) {
+	tests := []struct {
+		year int
+		leap bool
+	}{
+		{1, false},
+		{2, true},
+		{29, true},
+		{30, false},
+		{1444, false},
+		{1445, true},
+		{1446, false},
+		{1447, true},
+		{1448, false},
+	}
+	for _, tt := range tests {
+		if got := hijri.IsLeapYear(tt.year); got != tt.leap {
+			t.Errorf("IsLeapYear(%d) = %v, want %v", tt.year, got, tt.leap)
+		}
+
+		want := 29
+		if tt.leap {
+			want = 30
+		}
+		if got := hijri.DaysInMonth(tt.year, 12); got != want {
+			t.Errorf("DaysInMonth(%d, 12) = %d, want %d", tt.year, got, want)
+		}
+		if got := (hijri.Date{Year: tt.year, Month: 12, Day: 30}).Valid(); got != tt.leap {
+			t.Errorf("Date{%d, 12, 30}.Valid() = %v, want %v", tt.year, got, tt.leap)
+		}
+	}
+
+	// 30 Dhul Hijjah 1445 is followed by 1 Muharram 1446
+	if got, want := hijri.ToGregorian(hijri.Date{Year: 1445, Month: 12, Day: 30}, 0).AddDate(0, 0, 1), day(2024, time.July, 8); !got.Equal(want) {
+		t.Errorf("day after 30 Dhul Hijjah 1445 = %s, want %s", got.Format("2006-01-02"), want.Format("2006-01-02"))
+	}
+}
+
+func TestOffsetDays(t *testing.T) {
+	tests := []struct {
+		gregorian time.Time
+		offset    int
+		want      hijri.Date
+	}{
+		{day(2026, time.February, 25), 1, hijri.Date{Year: 1447, Month: 9, Day: 9}},
+		{day(2026, time.February, 25), -1, hijri.Date{Year: 1447, Month: 9, Day: 7}},
+		{day(2025, time.March, 1), -1, hijri.Date{Year: 1446, Month: 8, Day: 29}},
+		{day(2025, time.March, 30), 1, hijri.Date{Year: 1446, Month: 10, Day: 1}},
+		{day(2024, time.July, 7), 1, hijri.Date{Year: 1446, Month: 1, Day: 1}},
+		{day(2024, time.July, 8), -1, hijri.Date{Year: 1445, Month: 12, Day: 30}},
+	}
+	for _, tt := range tests {
+		if got := hijri.FromGregorian(tt.gregorian, tt.offset); got != tt.want {
+			t.Errorf("FromGregorian(%s, %d) = %v, want %v", tt.gregorian.Format("2006-01-02"), tt.offset, got, tt.want)
+		}
+		if got := hijri.ToGregorian(tt.want, tt.offset); !got.Equal(tt.gregorian) {
+			t.Errorf("ToGregorian(%v, %d) = %s, want %s", tt.want, tt.offset, got.Format("2006-01-02"), tt.gregorian.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		date hijri.Date
+		want bool
+	}{
+		{hijri.Date{Year: 1, Month: 1, Day: 1}, true},
+		{hijri.Date{Year: 0, Month: 12, Day: 29}, false},
+		{hijri.Date{Year: -640, Month: 5, Day: 18}, false},
+		{hijri.Date{Year: 1, Month: 0, Day: 1}, false},
+		{hijri.Date{Year: 1, Month: 13, Day: 1}, false},
+		{hijri.Date{Year: 1, Month: 1, Day: 0}, false},
+		{hijri.Date{Year: 1, Month: 1, Day: 30}, true},
+		{hijri.Date{Year: 1, Month: 1, Day: 31}, false},
+		{hijri.Date{Year: 1, Month: 2, Day: 30}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.date.Valid(); got != tt.want {
+			t.Errorf("%+v.Valid() = %v, want %v", tt.date, got, tt.want)
+		}
+	}
+
+	// The epoch is the first valid day, the day before it is not
+	if got := hijri.FromGregorian(day(622, time.July, 19), 0); !got.Valid() {
+		t.Errorf("FromGregorian(0622-07-19) = %+v, want a valid date", got)
+	}
+	if got := hijri.FromGregorian(day(622, time.July, 18), 0); got.Valid() {
+		t.Errorf("FromGregorian(0622-07-18) = %+v, want an invalid date", got)
+	}
+	if got := hijri.FromGregorian(day(1, time.January, 1), 0); got.Valid() {
+		t.Errorf("FromGregorian(0001-01-01) = %+v, want an invalid date", got)
+	}
+}
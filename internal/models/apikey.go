@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+// APIKey represents a credential used to access the API
+type APIKey struct {
+	ID        int        `json:"id" db:"id"`
+	Name      string     `json:"name" db:"name"`
+	Prefix    string     `json:"prefix" db:"prefix"`
+	KeyHash   string     `json:"-" db:"key_hash"`
+	Role      string     `json:"role" db:"role"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
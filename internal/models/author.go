@@ -0,0 +1,97 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Author represents a scholar, poet or other source of quotes. Quotes keep
+// the author's canonical name in Quote.Author and reference it by AuthorID.
+// Years are optional; negative Gregorian years are BCE.
+type Author struct {
+	ID             int       `json:"id" db:"id"`
+	Name           string    `json:"name" db:"name"`
+	NameArabic     string    `json:"name_arabic,omitempty" db:"name_arabic"`
+	Aliases        []string  `json:"aliases" db:"aliases"`
+	Kunya          string    `json:"kunya,omitempty" db:"kunya"`
+	BirthYearHijri *int      `json:"birth_year_hijri,omitempty" db:"birth_year_hijri"`
+	DeathYearHijri *int      `json:"death_year_hijri,omitempty" db:"death_year_hijri"`
+	BirthYear      *int      `json:"birth_year,omitempty" db:"birth_year"`
+	DeathYear      *int      `json:"death_year,omitempty" db:"death_year"`
+	Bio            string    `json:"bio,omitempty" db:"bio"`
+	Era            string    `json:"era,omitempty" db:"era"`
+	QuoteCount     int       `json:"quote_count"`
+	Quotes         []*Quote  `json:"quotes,omitempty"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// AuthorRequest represents the request structure for updating authors
+type AuthorRequest struct {
+	Name           string   `json:"name" validate:"required,max=255"`
+	NameArabic     string   `json:"name_arabic,omitempty" validate:"max=255,arabic"`
+	Aliases        []string `json:"aliases,omitempty"`
+	Kunya          string   `json:"kunya,omitempty" validate:"max=255"`
+	BirthYearHijri *int     `json:"birth_year_hijri,omitempty"`
+	DeathYearHijri *int     `json:"death_year_hijri,omitempty"`
+	BirthYear      *int     `json:"birth_year,omitempty"`
+	DeathYear      *int     `json:"death_year,omitempty"`
+	Bio            string   `json:"bio,omitempty"`
+	Era            string   `json:"era,omitempty" validate:"max=100"`
+}
+
+// MergeAuthorsRequest represents the request structure for merging an author
+// into another one
+type MergeAuthorsRequest struct {
+	TargetID int `json:"target_id"`
+}
+
+// AuthorResponse represents the response structure for a single author
+type AuthorResponse struct {
+	Success bool    `json:"success"`
+	Message string  `json:"message,omitempty"`
+	Data    *Author `json:"data,omitempty"`
+}
+
+// AuthorsResponse represents the response structure for a list of authors
+type AuthorsResponse struct {
+	Success bool      `json:"success"`
+	Data    []*Author `json:"data"`
+	Total   int       `json:"total"`
+}
+
+// Validate checks a against its validate tags, the aliases and the order of
+// the birth and death years
+func (a *AuthorRequest) Validate() ValidationErrors {
+	errs := validateStruct(a)
+
+	for _, alias := range a.Aliases {
+		if strings.TrimSpace(alias) == "" {
+			errs = append(errs, FieldError{Field: "aliases", Message: "must not contain empty names"})
+			break
+		}
+		if utf8.RuneCountInString(alias) > 255 {
+			errs = append(errs, FieldError{Field: "aliases", Message: "must be at most 255 characters each"})
+			break
+		}
+	}
+
+	checkYears := func(field string, birth, death *int) {
+		if birth != nil && death != nil && *death < *birth {
+			errs = append(errs, FieldError{Field: field, Message: "must not precede the birth year"})
+		}
+	}
+	checkYears("death_year_hijri", a.BirthYearHijri, a.DeathYearHijri)
+	checkYears("death_year", a.BirthYear, a.DeathYear)
+
+	return errs
+}
+
+// Validate checks that m names a target author
+func (m *MergeAuthorsRequest) Validate() ValidationErrors {
+	if m.TargetID <= 0 {
+		return ValidationErrors{{Field: "target_id", Message: "must be a positive author ID"}}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+)
+
+// Category is a node of the category taxonomy. A quote belongs to the
+// category whose Name equals its Quote.Category, ignoring case.
+type Category struct {
+	ID             int         `json:"id" db:"id"`
+	Slug           string      `json:"slug" db:"slug"`
+	Name           string      `json:"name" db:"name"`
+	NameArabic     string      `json:"name_arabic" db:"name_arabic"`
+	NameIndonesian string      `json:"name_indonesian" db:"name_indonesian"`
+	ParentID       *int        `json:"parent_id" db:"parent_id"`
+	QuoteCount     int         `json:"quote_count"`
+	Children       []*Category `json:"children,omitempty"`
+	CreatedAt      time.Time   `json:"created_at" db:"created_at"`
+}
+
+// CategoryDefinition describes a category of the default taxonomy, naming its
+// parent by slug
+type CategoryDefinition struct {
+	Slug           string
+	Name           string
+	NameArabic     string
+	NameIndonesian string
+	Parent         string
+}
+
+// CategoryResponse represents the response structure for a single category
+type CategoryResponse struct {
+	Success bool      `json:"success"`
+	Data    *Category `json:"data,omitempty"`
+}
+
+// CategoriesResponse represents the response structure for the category tree
+type CategoriesResponse struct {
+	Success bool        `json:"success"`
+	Data    []*Category `json:"data"`
+}
+
+// Names returns the names of c and all of its descendants, c first
+func (c *Category) Names() []string {
+	names := []string{c.Name}
+	for _, child := range c.Children {
+		names = append(names, child.Names()...)
+	}
+	return names
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"time"
+)
+
+// Occasion maps a recurring Hijri date range to the category preferred by the
+// daily quote during that range
+type Occasion struct {
+	ID         int       `json:"id" db:"id"`
+	Name       string    `json:"name" db:"name"`
+	StartMonth int       `json:"start_month" db:"start_month"`
+	StartDay   int       `json:"start_day" db:"start_day"`
+	EndMonth   int       `json:"end_month" db:"end_month"`
+	EndDay     int       `json:"end_day" db:"end_day"`
+	Category   string    `json:"category" db:"category"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// OccasionRequest represents the request structure for creating occasions
+type OccasionRequest struct {
+	Name       string `json:"name" validate:"required,max=100"`
+	StartMonth int    `json:"start_month"`
+	StartDay   int    `json:"start_day"`
+	EndMonth   int    `json:"end_month"`
+	EndDay     int    `json:"end_day"`
+	Category   string `json:"category" validate:"required,max=100"`
+}
+
+// OccasionResponse represents the response structure for a single occasion
+type OccasionResponse struct {
+	Success bool      `json:"success"`
+	Message string    `json:"message,omitempty"`
+	Data    *Occasion `json:"data,omitempty"`
+}
+
+// OccasionsResponse represents the response structure for multiple occasions
+type OccasionsResponse struct {
+	Success bool        `json:"success"`
+	Data    []*Occasion `json:"data"`
+}
+
+// Validate checks o against its validate tags and the Hijri month and day
+// ranges
+func (o *OccasionRequest) Validate() ValidationErrors {
+	errs := validateStruct(o)
+
+	checkMonth := func(field string, month int) {
+		if month < 1 || month > 12 {
+			errs = append(errs, FieldError{Field: field, Message: "must be between 1 and 12"})
+		}
+	}
+	checkDay := func(field string, day int) {
+		if day < 1 || day > 30 {
+			errs = append(errs, FieldError{Field: field, Message: "must be between 1 and 30"})
+		}
+	}
+
+	checkMonth("start_month", o.StartMonth)
+	checkDay("start_day", o.StartDay)
+	checkMonth("end_month", o.EndMonth)
+	checkDay("end_day", o.EndDay)
+
+	return errs
+}
+
+// Includes reports whether the Hijri month and day fall within the
+// occasion. Ranges whose end precedes their start wrap around the new year.
+func (o *Occasion) Includes(month, day int) bool {
+	value := month*100 + day
+	start := o.StartMonth*100 + o.StartDay
+	end := o.EndMonth*100 + o.EndDay
+	if start <= end {
+		return value >= start && value <= end
+	}
+	return value >= start || value <= end
+}
+
+// Length returns the approximate number of days covered by the occasion
+func (o *Occasion) Length() int {
+	days := (o.EndMonth-o.StartMonth)*30 + o.EndDay - o.StartDay + 1
+	if days <= 0 {
+		days += 12 * 30
+	}
+	return days
+}
+
+// HijriDate represents a Hijri calendar date in API responses
+type HijriDate struct {
+	Year            int    `json:"year"`
+	Month           int    `json:"month"`
+	Day             int    `json:"day"`
+	MonthName       string `json:"month_name"`
+	MonthNameArabic string `json:"month_name_arabic"`
+	Formatted       string `json:"formatted"`
+}
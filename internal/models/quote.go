@@ -1,30 +1,62 @@
 package models
 
 import (
+	"strings"
 	"time"
 )
 
-// Quote represents a mahfudzot (Arabic wisdom quote)
+// Quote represents a mahfudzot (Arabic wisdom quote). Tags always include
+// the primary Category, listed first.
 type Quote struct {
 	ID          int       `json:"id" db:"id"`
 	TextArabic  string    `json:"text_arabic" db:"text_arabic"`
 	TextLatin   string    `json:"text_latin,omitempty" db:"text_latin"`
 	Translation string    `json:"translation,omitempty" db:"translation"`
 	Author      string    `json:"author" db:"author"`
+	AuthorID    int       `json:"author_id,omitempty" db:"author_id"`
 	Category    string    `json:"category,omitempty" db:"category"`
 	Source      string    `json:"source,omitempty" db:"source"`
+	Tags        []string  `json:"tags"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 }
 
 // QuoteRequest represents the request structure for creating/updating quotes
 type QuoteRequest struct {
-	TextArabic  string `json:"text_arabic" validate:"required"`
-	TextLatin   string `json:"text_latin,omitempty"`
+	TextArabic  string `json:"text_arabic" validate:"required,arabic"`
+	TextLatin   string `json:"text_latin,omitempty" validate:"noarabic"`
 	Translation string `json:"translation,omitempty"`
-	Author      string `json:"author" validate:"required"`
-	Category    string `json:"category,omitempty"`
-	Source      string `json:"source,omitempty"`
+	Author      string `json:"author" validate:"required,max=255"`
+	Category    string `json:"category,omitempty" validate:"max=100"`
+	Source      string `json:"source,omitempty" validate:"max=255"`
+	// Tags are additional categories; the primary Category is always a tag
+	Tags []string `json:"tags,omitempty"`
+}
+
+// TagNames returns the tags of the quote described by r: its category
+// followed by Tags, trimmed, without empty names or duplicates ignoring case
+func (r *QuoteRequest) TagNames() []string {
+	names := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range append([]string{r.Category}, r.Tags...) {
+		tag = strings.TrimSpace(tag)
+		if key := strings.ToLower(tag); tag != "" && !seen[key] {
+			seen[key] = true
+			names = append(names, tag)
+		}
+	}
+	return names
+}
+
+// SecondaryTags returns the tags of q other than its primary category
+func (q *Quote) SecondaryTags() []string {
+	tags := []string{}
+	for _, tag := range q.Tags {
+		if !strings.EqualFold(tag, q.Category) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
 }
 
 // QuoteResponse represents the response structure for API calls
@@ -34,19 +66,97 @@ type QuoteResponse struct {
 	Data    *Quote `json:"data,omitempty"`
 }
 
+// DailyQuoteResponse represents the response structure for the quote of the day
+type DailyQuoteResponse struct {
+	Success  bool       `json:"success"`
+	Date     string     `json:"date"`
+	Timezone string     `json:"timezone"`
+	Hijri    *HijriDate `json:"hijri"`
+	Occasion string     `json:"occasion,omitempty"`
+	Data     *Quote     `json:"data"`
+}
+
 // QuotesResponse represents the response structure for multiple quotes
 type QuotesResponse struct {
-	Success bool     `json:"success"`
-	Message string   `json:"message,omitempty"`
-	Data    []*Quote `json:"data,omitempty"`
-	Total   int      `json:"total,omitempty"`
-	Page    int      `json:"page,omitempty"`
-	Limit   int      `json:"limit,omitempty"`
+	Success    bool     `json:"success"`
+	Message    string   `json:"message,omitempty"`
+	Data       []*Quote `json:"data,omitempty"`
+	Total      int      `json:"total"`
+	Page       int      `json:"page,omitempty"`
+	Limit      int      `json:"limit,omitempty"`
+	TotalPages int      `json:"total_pages"`
+	HasNext    bool     `json:"has_next"`
+	HasPrev    bool     `json:"has_prev"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+	Facets     *Facets  `json:"facets,omitempty"`
+}
+
+// FacetCount is the number of results sharing a value
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Facets counts the results of a listing per author, category and source,
+// most frequent values first
+type Facets struct {
+	Author   []FacetCount `json:"author"`
+	Category []FacetCount `json:"category"`
+	Source   []FacetCount `json:"source"`
+}
+
+// ValuesResponse lists distinct authors, categories or sources with their
+// number of quotes
+type ValuesResponse struct {
+	Success bool         `json:"success"`
+	Data    []FacetCount `json:"data"`
+	Total   int          `json:"total"`
+}
+
+// SearchResult is a quote found by a search, with its relevance score and
+// the matching fields highlighted (matches wrapped in <mark>, HTML-escaped)
+type SearchResult struct {
+	*Quote
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights,omitempty"`
 }
 
-// ErrorResponse represents error response structure
+// SearchResponse represents the response structure for search results
+type SearchResponse struct {
+	Success    bool            `json:"success"`
+	Message    string          `json:"message,omitempty"`
+	Data       []*SearchResult `json:"data"`
+	Total      int             `json:"total"`
+	Page       int             `json:"page,omitempty"`
+	Limit      int             `json:"limit,omitempty"`
+	TotalPages int             `json:"total_pages"`
+	HasNext    bool            `json:"has_next"`
+	HasPrev    bool            `json:"has_prev"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	Facets     *Facets         `json:"facets,omitempty"`
+}
+
+// ErrorResponse represents the legacy error response structure. Message is
+// a short summary of what failed and Error explains why.
 type ErrorResponse struct {
-	Success bool   `json:"success"`
-	Error   string `json:"error"`
-	Message string `json:"message,omitempty"`
+	Success bool         `json:"success"`
+	Error   string       `json:"error"`
+	Message string       `json:"message,omitempty"`
+	Errors  []FieldError `json:"errors,omitempty"`
+}
+
+// ProblemDetails represents an RFC 7807 application/problem+json document
+type ProblemDetails struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes a problem with a single request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/albantanie/mahfudzot-generator/internal/arabic"
+)
+
+// ValidationErrors collects every field-level problem found in a request
+type ValidationErrors []FieldError
+
+// Error implements the error interface
+func (v ValidationErrors) Error() string {
+	parts := make([]string, len(v))
+	for i, fe := range v {
+		parts[i] = fe.Field + " " + fe.Message
+	}
+	return strings.Join(parts, "; ")
+}
+
+// maxQuoteTags limits the number of tags of a quote
+const maxQuoteTags = 20
+
+// Validate checks q against the rules in its validate struct tags and its
+// tags, which must be non-empty and fit in VARCHAR(100)
+func (q *QuoteRequest) Validate() ValidationErrors {
+	errs := validateStruct(q)
+
+	if len(q.Tags) > maxQuoteTags {
+		errs = append(errs, FieldError{Field: "tags", Message: fmt.Sprintf("must contain at most %d tags", maxQuoteTags)})
+	}
+	for _, tag := range q.Tags {
+		if strings.TrimSpace(tag) == "" {
+			errs = append(errs, FieldError{Field: "tags", Message: "must not contain empty tags"})
+			break
+		}
+		if utf8.RuneCountInString(tag) > 100 {
+			errs = append(errs, FieldError{Field: "tags", Message: "must be at most 100 characters each"})
+			break
+		}
+	}
+
+	return errs
+}
+
+// validateStruct applies the rules declared in the validate tags of the
+// struct pointed to by v. Supported rules are:
+//
+//	required  the trimmed value must not be empty
+//	max=N     at most N characters (runes), matching SQL VARCHAR(N)
+//	arabic    must contain at least one Arabic-script letter
+//	noarabic  must not contain any Arabic-script letter
+//
+// Fields are reported by their JSON name and all violations are returned.
+func validateStruct(v interface{}) ValidationErrors {
+	var errs ValidationErrors
+
+	rv := reflect.ValueOf(v).Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag := field.Tag.Get("validate")
+		if tag == "" || field.Type.Kind() != reflect.String {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+
+		value := rv.Field(i).String()
+		for _, rule := range strings.Split(tag, ",") {
+			if msg := checkRule(rule, value); msg != "" {
+				errs = append(errs, FieldError{Field: name, Message: msg})
+				if rule == "required" {
+					break
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// checkRule returns a description of how value violates rule, or ""
+func checkRule(rule, value string) string {
+	name, arg, _ := strings.Cut(rule, "=")
+	switch name {
+	case "required":
+		if strings.TrimSpace(value) == "" {
+			return "is required"
+		}
+	case "max":
+		limit, err := strconv.Atoi(arg)
+		if err != nil {
+			panic(fmt.Sprintf("invalid validate rule %q", rule))
+		}
+		if utf8.RuneCountInString(value) > limit {
+			return fmt.Sprintf("must be at most %d characters", limit)
+		}
+	case "arabic":
+		if value != "" && !arabic.ContainsArabic(value) {
+			return "must contain Arabic script"
+		}
+	case "noarabic":
+		if arabic.ContainsArabic(value) {
+			return "must not contain Arabic script"
+		}
+	default:
+		panic(fmt.Sprintf("unknown validate rule %q", rule))
+	}
+	return ""
+}
@@ -0,0 +1,158 @@
+// Package shuffle remembers which quotes each client has been served so that
+// every quote is shown once before any repeats. State lives in a bounded
+// in-memory store: the least recently used clients are evicted first.
+package shuffle
+
+import (
+	"container/list"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Store holds the shuffle state of up to a fixed number of clients
+type Store struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	sessions map[string]*list.Element
+	order    *list.List // most recently used at the front
+}
+
+// session is the state of one client's current cycle
+type session struct {
+	token    string
+	served   map[int]struct{}
+	cycle    int
+	lastUsed time.Time
+}
+
+// NewStore creates a store keeping at most capacity clients, each forgotten
+// after ttl without requests. It panics if capacity or ttl is not positive.
+func NewStore(capacity int, ttl time.Duration) *Store {
+	if capacity <= 0 {
+		panic("shuffle: non-positive capacity for NewStore")
+	}
+	if ttl <= 0 {
+		panic("shuffle: non-positive ttl for NewStore")
+	}
+
+	return &Store{
+		capacity: capacity,
+		ttl:      ttl,
+		sessions: make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// NewToken registers a new client and returns its token
+func (s *Store) NewToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions[token] = s.order.PushFront(&session{
+		token:    token,
+		served:   make(map[int]struct{}),
+		cycle:    1,
+		lastUsed: time.Now(),
+	})
+	s.evict()
+
+	return token, nil
+}
+
+// Served returns the ids served to token in the current cycle and the cycle
+// number. ok is false for unknown or expired tokens.
+func (s *Store) Served(token string) (ids []int, cycle int, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess := s.touch(token)
+	if sess == nil {
+		return nil, 0, false
+	}
+
+	ids = make([]int, 0, len(sess.served))
+	for id := range sess.served {
+		ids = append(ids, id)
+	}
+	return ids, sess.cycle, true
+}
+
+// Reserve records ids as served to token in cycle and reports whether it
+// did. Nothing is recorded when the token is unknown, cycle is no longer
+// current or any of ids was already served, so concurrent requests with the
+// same token never reserve the same quote; the caller picks again instead.
+func (s *Store) Reserve(token string, cycle int, ids ...int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess := s.touch(token)
+	if sess == nil || sess.cycle != cycle {
+		return false
+	}
+	for _, id := range ids {
+		if _, ok := sess.served[id]; ok {
+			return false
+		}
+	}
+
+	for _, id := range ids {
+		sess.served[id] = struct{}{}
+	}
+	return true
+}
+
+// Restart begins a new cycle for token after cycle, forgetting what it was
+// served, and returns the current cycle. Nothing changes if another request
+// already started a new cycle. Quotes deleted during the previous cycle are
+// dropped along with it.
+func (s *Store) Restart(token string, cycle int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess := s.touch(token)
+	if sess == nil {
+		return 0
+	}
+	if sess.cycle == cycle {
+		sess.served = make(map[int]struct{})
+		sess.cycle++
+	}
+	return sess.cycle
+}
+
+// touch returns the live session of token, marking it most recently used
+func (s *Store) touch(token string) *session {
+	elem, ok := s.sessions[token]
+	if !ok {
+		return nil
+	}
+
+	sess := elem.Value.(*session)
+	if time.Since(sess.lastUsed) > s.ttl {
+		s.order.Remove(elem)
+		delete(s.sessions, token)
+		return nil
+	}
+
+	sess.lastUsed = time.Now()
+	s.order.MoveToFront(elem)
+	return sess
+}
+
+// evict drops the least recently used sessions beyond capacity
+func (s *Store) evict() {
+	for s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.sessions, oldest.Value.(*session).token)
+	}
+}
@@ -0,0 +1,185 @@
+// Package translit matches Latin transliterations of Arabic text while
+// tolerating the many ways the same words are spelled: with or without ʿayn
+// and hamza marks, hyphenated articles, doubled vowels and consonants, and
+// English or Indonesian conventions such as "dh"/"dz" or "sh"/"sy".
+package translit
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// marks are the characters used for ʿayn and hamza, which are dropped
+var marks = map[rune]bool{
+	'\'': true, '`': true, '´': true, '‘': true, '’': true,
+	'ʼ': true, 'ʻ': true, 'ʽ': true, 'ʾ': true, 'ʿ': true, '′': true,
+}
+
+// variants folds spelling conventions onto one form. Digraphs come first so
+// that they win over the single letters they contain.
+var variants = strings.NewReplacer(
+	"dh", "d", "dz", "d", // ذ and ض
+	"sy", "sh", // ش
+	"ts", "th", // ث
+	"dj", "j", // ج
+	"ch", "kh", // خ
+	"aw", "au", "ay", "ai", // diphthongs
+	"q", "k",
+	"e", "i",
+	"o", "u",
+)
+
+// articles are standalone words that carry no meaning for matching
+var articles = map[string]bool{"al": true, "el": true, "ul": true}
+
+// Words splits s into folded words. Articles and the short particles
+// hyphenated to a word ("al-", "wal-", "bin-", "li-") are dropped, marks are
+// removed, spelling variants unified, doubled letters collapsed and a final
+// "h" after a vowel (taa marbuta) removed.
+func Words(s string) []string {
+	s = strings.ToLower(norm.NFD.String(s))
+
+	var words []string
+	for _, field := range strings.FieldsFunc(s, unicode.IsSpace) {
+		parts := splitWord(field)
+		for i, part := range parts {
+			// the first part of a hyphenated compound is an article or particle
+			if i < len(parts)-1 && len(part) <= 3 {
+				continue
+			}
+			if articles[part] {
+				continue
+			}
+			if word := fold(part); word != "" {
+				words = append(words, word)
+			}
+		}
+	}
+	return words
+}
+
+// Fold returns the folded form of s, its words joined by single spaces
+func Fold(s string) string {
+	return strings.Join(Words(s), " ")
+}
+
+// splitWord removes marks and combining accents from a whitespace-separated
+// field and splits it on every other non-alphanumeric character, such as
+// hyphens
+func splitWord(field string) []string {
+	var b strings.Builder
+	for _, r := range field {
+		switch {
+		case marks[r] || unicode.Is(unicode.Mn, r):
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Fields(b.String())
+}
+
+// fold applies the spelling rules to a single word
+func fold(word string) string {
+	word = variants.Replace(word)
+
+	runes := make([]rune, 0, len(word))
+	for _, r := range word {
+		if n := len(runes); n > 0 && runes[n-1] == r {
+			continue
+		}
+		runes = append(runes, r)
+	}
+
+	if n := len(runes); n > 2 && runes[n-1] == 'h' && isVowel(runes[n-2]) {
+		runes = runes[:n-1]
+	}
+	return string(runes)
+}
+
+func isVowel(r rune) bool {
+	return r == 'a' || r == 'i' || r == 'u'
+}
+
+// Match reports whether every word of query approximately occurs in text and
+// how far off the closest match is, as the sum over query words of the edit
+// distance to the best matching prefix of a text word. Prefixes are compared
+// so that "nur" finds "nurun" despite the case ending. Each word tolerates
+// no edits up to three letters, one up to six and two beyond that.
+func Match(query, text string) (distance int, ok bool) {
+	queryWords := Words(query)
+	if len(queryWords) == 0 {
+		return 0, false
+	}
+	textWords := Words(text)
+
+	for _, q := range queryWords {
+		best := -1
+		for _, t := range textWords {
+			if d := prefixDistance(q, t); best < 0 || d < best {
+				best = d
+			}
+		}
+		if best < 0 || best > tolerance(q) {
+			return 0, false
+		}
+		distance += best
+	}
+	return distance, true
+}
+
+// tolerance is the number of edits allowed when matching word
+func tolerance(word string) int {
+	switch n := len([]rune(word)); {
+	case n <= 3:
+		return 0
+	case n <= 6:
+		return 1
+	}
+	return 2
+}
+
+// Distance returns the Levenshtein distance between a and b
+func Distance(a, b string) int {
+	row := editRow([]rune(a), []rune(b))
+	return row[len(row)-1]
+}
+
+// prefixDistance returns the smallest Levenshtein distance between q and a
+// prefix of t
+func prefixDistance(q, t string) int {
+	row := editRow([]rune(q), []rune(t))
+	best := row[0]
+	for _, d := range row[1:] {
+		if d < best {
+			best = d
+		}
+	}
+	return best
+}
+
+// editRow returns the last row of the Levenshtein table of a against b: the
+// distance between a and each prefix of b, from the empty prefix to all of b
+func editRow(a, b []rune) []int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
+	_ "time/tzdata" // embed zone data for the daily quote tz parameter in minimal images
 
+	"github.com/albantanie/mahfudzot-generator/internal/auth"
 	"github.com/albantanie/mahfudzot-generator/internal/config"
 	"github.com/albantanie/mahfudzot-generator/internal/database"
 	"github.com/albantanie/mahfudzot-generator/internal/handlers"
+	"github.com/albantanie/mahfudzot-generator/internal/shuffle"
 	"github.com/gorilla/mux"
 )
 
@@ -15,7 +20,7 @@ func main() {
 	cfg := config.Load()
 
 	// Initialize database connection
-	var quoteHandler *handlers.QuoteHandler
+	var store database.Store
 
 	// Try to connect to database, fallback to mock if it fails
 	db, err := database.New(&cfg.Database)
@@ -23,8 +28,7 @@ func main() {
 		log.Printf("Warning: Database connection failed: %v", err)
 		log.Println("Running in demo mode with mock data")
 		// Create mock database for demo
-		mockDB := database.NewMockDB()
-		quoteHandler = handlers.NewQuoteHandler(mockDB)
+		store = database.NewMockDB()
 	} else {
 		log.Println("Connected to database successfully")
 		// Check if quotes table exists, if not use mock data
@@ -33,14 +37,26 @@ func main() {
 			log.Printf("Warning: Quotes table not found: %v", err)
 			log.Println("Running in demo mode with mock data")
 			db.Close()
-			mockDB := database.NewMockDB()
-			quoteHandler = handlers.NewQuoteHandler(mockDB)
+			store = database.NewMockDB()
 		} else {
-			quoteHandler = handlers.NewQuoteHandler(db)
+			store = db
 			defer db.Close()
 		}
 	}
 
+	// Register the bootstrap admin key, if configured
+	if cfg.Auth.BootstrapKey != "" {
+		registerBootstrapKey(context.Background(), store, cfg.Auth.BootstrapKey)
+	}
+
+	shuffles := shuffle.NewStore(cfg.Shuffle.MaxClients, cfg.Shuffle.TTL)
+	quoteHandler := handlers.NewQuoteHandler(store, store, store, shuffles)
+	dailyHandler := handlers.NewDailyHandler(store, store, cfg.Daily.HijriOffset)
+	valueHandler := handlers.NewValueHandler(store)
+	authorHandler := handlers.NewAuthorHandler(store, store)
+	categoryHandler := handlers.NewCategoryHandler(store)
+	authz := handlers.NewAuthMiddleware(store, cfg.Auth.PublicRead)
+
 	// Create router
 	router := mux.NewRouter()
 
@@ -52,22 +68,64 @@ func main() {
 
 	// API routes
 	api := router.PathPrefix("/api/v1").Subrouter()
-	api.HandleFunc("/quotes", quoteHandler.GetQuotes).Methods("GET")
-	api.HandleFunc("/quotes/random", quoteHandler.GetRandomQuote).Methods("GET")
-	api.HandleFunc("/quotes/{id:[0-9]+}", quoteHandler.GetQuoteByID).Methods("GET")
-	api.HandleFunc("/quotes/author/{author}", quoteHandler.GetQuotesByAuthor).Methods("GET")
-	api.HandleFunc("/quotes/category/{category}", quoteHandler.GetQuotesByCategory).Methods("GET")
+	api.Use(authz.Authenticate)
+	api.Handle("/quotes", authz.Require(auth.RoleReader, quoteHandler.GetQuotes)).Methods("GET")
+	api.Handle("/quotes/search", authz.Require(auth.RoleReader, quoteHandler.SearchQuotes)).Methods("GET")
+	api.Handle("/quotes/random", authz.Require(auth.RoleReader, quoteHandler.GetRandomQuote)).Methods("GET")
+	api.Handle("/quotes/daily", authz.Require(auth.RoleReader, dailyHandler.GetDailyQuote)).Methods("GET")
+	api.Handle("/quotes/daily/{date}", authz.Require(auth.RoleReader, dailyHandler.GetDailyQuote)).Methods("GET")
+	api.Handle("/quotes/{id:[0-9]+}", authz.Require(auth.RoleReader, quoteHandler.GetQuoteByID)).Methods("GET")
+	api.Handle("/quotes/author/{author}", authz.Require(auth.RoleReader, quoteHandler.GetQuotesByAuthor)).Methods("GET")
+	api.Handle("/quotes/category/{category}", authz.Require(auth.RoleReader, quoteHandler.GetQuotesByCategory)).Methods("GET")
+	api.Handle("/quotes", authz.Require(auth.RoleEditor, quoteHandler.CreateQuote)).Methods("POST")
+	api.Handle("/quotes/{id:[0-9]+}", authz.Require(auth.RoleEditor, quoteHandler.UpdateQuote)).Methods("PUT")
+	api.Handle("/quotes/{id:[0-9]+}", authz.Require(auth.RoleEditor, quoteHandler.PatchQuote)).Methods("PATCH")
+	api.Handle("/quotes/{id:[0-9]+}", authz.Require(auth.RoleAdmin, quoteHandler.DeleteQuote)).Methods("DELETE")
+	api.Handle("/authors", authz.Require(auth.RoleReader, authorHandler.ListAuthors)).Methods("GET")
+	api.Handle("/authors/suggest", authz.Require(auth.RoleReader, valueHandler.SuggestAuthors)).Methods("GET")
+	api.Handle("/authors/{id:[0-9]+}", authz.Require(auth.RoleReader, authorHandler.GetAuthor)).Methods("GET")
+	api.Handle("/authors/{id:[0-9]+}", authz.Require(auth.RoleEditor, authorHandler.UpdateAuthor)).Methods("PUT")
+	api.Handle("/authors/{id:[0-9]+}/merge", authz.Require(auth.RoleAdmin, authorHandler.MergeAuthor)).Methods("POST")
+	api.Handle("/categories", authz.Require(auth.RoleReader, valueHandler.GetCategories)).Methods("GET")
+	api.Handle("/categories/suggest", authz.Require(auth.RoleReader, valueHandler.SuggestCategories)).Methods("GET")
+	api.Handle("/categories/tree", authz.Require(auth.RoleReader, categoryHandler.GetCategoryTree)).Methods("GET")
+	api.Handle("/categories/{slug}", authz.Require(auth.RoleReader, categoryHandler.GetCategory)).Methods("GET")
+	api.Handle("/sources", authz.Require(auth.RoleReader, valueHandler.GetSources)).Methods("GET")
+	api.Handle("/sources/suggest", authz.Require(auth.RoleReader, valueHandler.SuggestSources)).Methods("GET")
+	api.Handle("/occasions", authz.Require(auth.RoleReader, dailyHandler.GetOccasions)).Methods("GET")
+	api.Handle("/occasions", authz.Require(auth.RoleAdmin, dailyHandler.CreateOccasion)).Methods("POST")
+	api.Handle("/occasions/{id:[0-9]+}", authz.Require(auth.RoleAdmin, dailyHandler.DeleteOccasion)).Methods("DELETE")
 
 	log.Printf("Server starting on port %s", cfg.Server.Port)
 	log.Fatal(http.ListenAndServe(":"+cfg.Server.Port, router))
 }
 
+// registerBootstrapKey makes sure key exists as an admin key in store
+func registerBootstrapKey(ctx context.Context, store database.APIKeyRepository, key string) {
+	hash := auth.HashKey(key)
+	_, err := store.GetAPIKeyByHash(ctx, hash)
+	if err == nil {
+		return
+	}
+	if !errors.Is(err, database.ErrNotFound) {
+		log.Printf("Warning: Failed to look up bootstrap API key: %v", err)
+		return
+	}
+
+	if _, err := store.CreateAPIKey(ctx, "bootstrap", string(auth.RoleAdmin), hash, auth.KeyPrefix(key)); err != nil {
+		log.Printf("Warning: Failed to register bootstrap API key: %v", err)
+		return
+	}
+	log.Println("Registered bootstrap admin API key")
+}
+
 // corsMiddleware adds CORS headers
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Shuffle-Token")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Random-Seed, X-Shuffle-Token, X-Shuffle-Cycle")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -0,0 +1,17 @@
+-- Create occasions table mapping Hijri date ranges to daily quote categories
+CREATE TABLE IF NOT EXISTS occasions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    start_month SMALLINT NOT NULL CHECK (start_month BETWEEN 1 AND 12),
+    start_day SMALLINT NOT NULL CHECK (start_day BETWEEN 1 AND 30),
+    end_month SMALLINT NOT NULL CHECK (end_month BETWEEN 1 AND 12),
+    end_day SMALLINT NOT NULL CHECK (end_day BETWEEN 1 AND 30),
+    category VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Default occasions, kept in sync with database.DefaultOccasions
+INSERT INTO occasions (name, start_month, start_day, end_month, end_day, category) VALUES
+('Islamic New Year', 1, 1, 1, 10, 'Time'),
+('Ramadan', 9, 1, 9, 30, 'Patience'),
+('First ten days of Dhul Hijjah', 12, 1, 12, 10, 'Spirituality');
@@ -0,0 +1,21 @@
+-- Arabic-aware search over text_arabic, text_latin and translation
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Strip tashkeel, Quranic marks and tatweel, unify alef/hamza forms, taa
+-- marbuta and alef maqsura, and lower-case. Keep in sync with
+-- arabic.Fold in internal/arabic.
+CREATE OR REPLACE FUNCTION normalize_arabic(input TEXT)
+RETURNS TEXT AS $$
+    SELECT lower(translate(
+        regexp_replace(input, '[\u064B-\u065F\u0670\u06D6-\u06ED\u0640]', '', 'g'),
+        'أإآٱؤئةى',
+        'ااااويهي'
+    ))
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE;
+
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS search_text TEXT
+    GENERATED ALWAYS AS (
+        normalize_arabic(text_arabic || E'\n' || COALESCE(text_latin, '') || E'\n' || COALESCE(translation, ''))
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_quotes_search_text ON quotes USING GIN (search_text gin_trgm_ops);
@@ -0,0 +1,56 @@
+-- Authors as first-class entities. Quotes keep the author name for
+-- compatibility and reference the author through author_id, which is
+-- resolved from the name on every write.
+CREATE TABLE IF NOT EXISTS authors (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    name_arabic VARCHAR(255),
+    kunya VARCHAR(255),
+    birth_year_hijri SMALLINT,
+    death_year_hijri SMALLINT,
+    birth_year SMALLINT,
+    death_year SMALLINT,
+    bio TEXT,
+    era VARCHAR(100),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_authors_updated_at
+    BEFORE UPDATE ON authors
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Backfill one author per distinct author string
+INSERT INTO authors (name)
+SELECT DISTINCT author FROM quotes
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS author_id INTEGER REFERENCES authors(id);
+
+UPDATE quotes q
+SET author_id = a.id
+FROM authors a
+WHERE a.name = q.author AND q.author_id IS NULL;
+
+ALTER TABLE quotes ALTER COLUMN author_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_quotes_author_id ON quotes(author_id);
+
+-- Link new and renamed quotes to the author with that name, creating it if
+-- needed. Keep in sync with MockDB.resolveAuthor.
+CREATE OR REPLACE FUNCTION set_quote_author_id()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' OR NEW.author IS DISTINCT FROM OLD.author THEN
+        INSERT INTO authors (name) VALUES (NEW.author) ON CONFLICT (name) DO NOTHING;
+        SELECT id INTO NEW.author_id FROM authors WHERE name = NEW.author;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER set_quotes_author_id
+    BEFORE INSERT OR UPDATE ON quotes
+    FOR EACH ROW
+    EXECUTE FUNCTION set_quote_author_id();
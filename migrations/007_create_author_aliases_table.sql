@@ -0,0 +1,67 @@
+-- Alternative spellings of author names. An alias belongs to exactly one
+-- author and is matched case-insensitively when looking up authors and when
+-- quotes are written, so every spelling ends up on the canonical author.
+CREATE TABLE IF NOT EXISTS author_aliases (
+    id SERIAL PRIMARY KEY,
+    author_id INTEGER NOT NULL REFERENCES authors(id) ON DELETE CASCADE,
+    alias VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_author_aliases_alias ON author_aliases(LOWER(alias));
+CREATE INDEX IF NOT EXISTS idx_author_aliases_author_id ON author_aliases(author_id);
+CREATE INDEX IF NOT EXISTS idx_authors_name_lower ON authors(LOWER(name));
+
+-- Known spellings of the seed authors. Keep in sync with GetSeedAliases.
+INSERT INTO author_aliases (author_id, alias)
+SELECT a.id, v.alias
+FROM (VALUES
+    ('Prophet Muhammad', 'Rasulullah'),
+    ('Prophet Muhammad', 'Nabi Muhammad'),
+    ('Imam Ali', 'Ali ibn Abi Talib'),
+    ('Imam Ali', 'Ali bin Abi Thalib'),
+    ('Imam Al-Ghazali', 'Al-Ghazali'),
+    ('Imam Al-Ghazali', 'Abu Hamid al-Ghazali'),
+    ('Imam Ash-Shafi''i', 'Imam Syafi''i'),
+    ('Imam Ash-Shafi''i', 'Al-Shafi''i'),
+    ('Imam Ahmad ibn Hanbal', 'Imam Ahmad'),
+    ('Imam Ahmad ibn Hanbal', 'Ahmad bin Hanbal'),
+    ('Imam An-Nawawi', 'Imam Nawawi'),
+    ('Imam An-Nawawi', 'Al-Nawawi'),
+    ('Imam Al-Bukhari', 'Imam Bukhari'),
+    ('Imam Al-Bukhari', 'Al-Bukhari'),
+    ('Ibn al-Qayyim', 'Ibn Qayyim al-Jawziyya'),
+    ('Ibn al-Qayyim', 'Ibnul Qayyim'),
+    ('Ibn Sina', 'Avicenna'),
+    ('Ibn Rushd', 'Averroes'),
+    ('Rumi', 'Jalaluddin Rumi'),
+    ('Rumi', 'Mawlana Rumi')
+) AS v(name, alias)
+JOIN authors a ON a.name = v.name
+WHERE NOT EXISTS (SELECT 1 FROM authors o WHERE LOWER(o.name) = LOWER(v.alias))
+ON CONFLICT DO NOTHING;
+
+-- Link new and renamed quotes to the author with that name or alias,
+-- replacing an alias by the canonical name and creating the author if
+-- needed. Keep in sync with MockDB.resolveAuthor.
+CREATE OR REPLACE FUNCTION set_quote_author_id()
+RETURNS TRIGGER AS $$
+DECLARE
+    canonical VARCHAR(255);
+BEGIN
+    IF TG_OP = 'INSERT' OR NEW.author IS DISTINCT FROM OLD.author THEN
+        SELECT a.name INTO canonical
+        FROM author_aliases al
+        JOIN authors a ON a.id = al.author_id
+        WHERE LOWER(al.alias) = LOWER(NEW.author);
+
+        IF canonical IS NOT NULL THEN
+            NEW.author := canonical;
+        END IF;
+
+        INSERT INTO authors (name) VALUES (NEW.author) ON CONFLICT (name) DO NOTHING;
+        SELECT id INTO NEW.author_id FROM authors WHERE name = NEW.author;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,75 @@
+-- Category taxonomy. Quotes keep their category as a string and belong to
+-- the category with that name, ignoring case, so the taxonomy can group
+-- existing categories without touching the quotes.
+CREATE TABLE IF NOT EXISTS categories (
+    id SERIAL PRIMARY KEY,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    name_arabic VARCHAR(100) NOT NULL,
+    name_indonesian VARCHAR(100) NOT NULL,
+    parent_id INTEGER REFERENCES categories(id) ON DELETE RESTRICT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_name ON categories(LOWER(name));
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
+CREATE INDEX IF NOT EXISTS idx_quotes_category_lower ON quotes(LOWER(category));
+
+-- Default taxonomy covering every seed category, kept in sync with
+-- database.DefaultCategories
+CREATE TEMPORARY TABLE default_categories (slug, name, name_arabic, name_indonesian, parent) AS VALUES
+    ('knowledge', 'Knowledge', 'العلم', 'Ilmu', NULL),
+    ('learning', 'Learning', 'التعلم', 'Belajar', 'knowledge'),
+    ('scholarship', 'Scholarship', 'العلماء', 'Keulamaan', 'knowledge'),
+    ('reason', 'Reason', 'العقل', 'Akal', 'knowledge'),
+    ('philosophy', 'Philosophy', 'الفلسفة', 'Filsafat', 'knowledge'),
+    ('self-knowledge', 'Self-Knowledge', 'معرفة النفس', 'Mengenal Diri', 'knowledge'),
+    ('history', 'History', 'التاريخ', 'Sejarah', 'knowledge'),
+    ('universe', 'Universe', 'الكون', 'Alam Semesta', 'knowledge'),
+    ('akhlaq', 'Akhlaq', 'الأخلاق', 'Akhlak', NULL),
+    ('character', 'Character', 'الخلق', 'Budi Pekerti', 'akhlaq'),
+    ('ethics', 'Ethics', 'الآداب', 'Etika', 'akhlaq'),
+    ('humility', 'Humility', 'التواضع', 'Rendah Hati', 'akhlaq'),
+    ('patience', 'Patience', 'الصبر', 'Sabar', 'akhlaq'),
+    ('intention', 'Intention', 'النية', 'Niat', 'akhlaq'),
+    ('truth', 'Truth', 'الصدق', 'Kejujuran', 'akhlaq'),
+    ('justice', 'Justice', 'العدل', 'Keadilan', 'akhlaq'),
+    ('effort', 'Effort', 'الجد', 'Kesungguhan', 'akhlaq'),
+    ('determination', 'Determination', 'العزيمة', 'Tekad', 'effort'),
+    ('excellence', 'Excellence', 'الإتقان', 'Keunggulan', 'effort'),
+    ('ibadah', 'Ibadah', 'العبادة', 'Ibadah', NULL),
+    ('faith', 'Faith', 'الإيمان', 'Iman', 'ibadah'),
+    ('prayer', 'Prayer', 'الصلاة', 'Shalat', 'ibadah'),
+    ('quran', 'Quran', 'القرآن', 'Al-Qur''an', 'ibadah'),
+    ('spirituality', 'Spirituality', 'الروحانية', 'Spiritualitas', 'ibadah'),
+    ('jurisprudence', 'Jurisprudence', 'الفقه', 'Fikih', 'ibadah'),
+    ('society', 'Society', 'المجتمع', 'Masyarakat', NULL),
+    ('brotherhood', 'Brotherhood', 'الأخوة', 'Persaudaraan', 'society'),
+    ('friendship', 'Friendship', 'الصداقة', 'Persahabatan', 'brotherhood'),
+    ('love', 'Love', 'الحب', 'Cinta', 'society'),
+    ('humanity', 'Humanity', 'الإنسانية', 'Kemanusiaan', 'society'),
+    ('service', 'Service', 'خدمة الناس', 'Pengabdian', 'society'),
+    ('freedom', 'Freedom', 'الحرية', 'Kebebasan', 'society'),
+    ('reform', 'Reform', 'الإصلاح', 'Perbaikan', 'society'),
+    ('progress', 'Progress', 'التقدم', 'Kemajuan', 'society'),
+    ('life', 'Life', 'الحياة', 'Kehidupan', NULL),
+    ('wisdom', 'Wisdom', 'الحكمة', 'Hikmah', 'life'),
+    ('perspective', 'Perspective', 'النظرة', 'Sudut Pandang', 'life'),
+    ('happiness', 'Happiness', 'السعادة', 'Kebahagiaan', 'life'),
+    ('time', 'Time', 'الوقت', 'Waktu', 'life'),
+    ('travel', 'Travel', 'السفر', 'Perjalanan', 'life'),
+    ('health', 'Health', 'الصحة', 'Kesehatan', 'life'),
+    ('prevention', 'Prevention', 'الوقاية', 'Pencegahan', 'health')
+;
+
+INSERT INTO categories (slug, name, name_arabic, name_indonesian)
+SELECT slug, name, name_arabic, name_indonesian FROM default_categories
+ON CONFLICT (slug) DO NOTHING;
+
+UPDATE categories c
+SET parent_id = p.id
+FROM default_categories d
+JOIN categories p ON p.slug = d.parent
+WHERE c.slug = d.slug AND c.parent_id IS NULL;
+
+DROP TABLE default_categories;
@@ -0,0 +1,42 @@
+-- Free-form tags, many per quote. quotes.category stays the primary
+-- category and is always one of the quote's tags. Tags are matched
+-- case-insensitively; the first spelling stored is kept.
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name ON tags(LOWER(name));
+
+CREATE TABLE IF NOT EXISTS quote_tags (
+    quote_id INTEGER NOT NULL REFERENCES quotes(id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (quote_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quote_tags_tag_id ON quote_tags(tag_id);
+
+-- Every existing category becomes the primary tag of its quotes
+INSERT INTO tags (name)
+SELECT DISTINCT ON (LOWER(category)) category
+FROM quotes
+WHERE COALESCE(category, '') <> ''
+ORDER BY LOWER(category), category
+ON CONFLICT DO NOTHING;
+
+INSERT INTO quote_tags (quote_id, tag_id)
+SELECT q.id, t.id
+FROM quotes q
+JOIN tags t ON LOWER(t.name) = LOWER(q.category)
+ON CONFLICT DO NOTHING;
+
+-- Additional tags of the seed quotes, kept in sync with GetSeedData
+INSERT INTO tags (name) VALUES ('Hope') ON CONFLICT DO NOTHING;
+
+INSERT INTO quote_tags (quote_id, tag_id)
+SELECT q.id, t.id
+FROM quotes q
+JOIN tags t ON LOWER(t.name) = 'hope'
+WHERE q.text_arabic = 'الصبر مفتاح الفرج'
+ON CONFLICT DO NOTHING;
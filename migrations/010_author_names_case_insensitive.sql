@@ -0,0 +1,60 @@
+-- Author names are unique ignoring case, like aliases, so "imam ali" is
+-- attributed to "Imam Ali" instead of creating a second author.
+
+-- Merge authors whose names differ only in case into the oldest one
+CREATE TEMPORARY TABLE duplicate_authors AS
+SELECT a.id, k.id AS keep_id, k.name AS keep_name
+FROM authors a
+JOIN LATERAL (
+    SELECT o.id, o.name FROM authors o
+    WHERE LOWER(o.name) = LOWER(a.name)
+    ORDER BY o.id
+    LIMIT 1
+) k ON k.id <> a.id;
+
+UPDATE quotes q
+SET author = d.keep_name, author_id = d.keep_id
+FROM duplicate_authors d
+WHERE q.author_id = d.id;
+
+UPDATE author_aliases al
+SET author_id = d.keep_id
+FROM duplicate_authors d
+WHERE al.author_id = d.id;
+
+DELETE FROM authors WHERE id IN (SELECT id FROM duplicate_authors);
+
+DROP TABLE duplicate_authors;
+
+DROP INDEX IF EXISTS idx_authors_name_lower;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_authors_name_lower ON authors(LOWER(name));
+
+-- Link new and renamed quotes to the author with that name or alias, both
+-- ignoring case, replacing it by the stored spelling of the author's name
+-- and creating the author if needed. Keep in sync with MockDB.resolveAuthor.
+CREATE OR REPLACE FUNCTION set_quote_author_id()
+RETURNS TRIGGER AS $$
+DECLARE
+    canonical VARCHAR(255);
+BEGIN
+    IF TG_OP = 'INSERT' OR NEW.author IS DISTINCT FROM OLD.author THEN
+        SELECT name INTO canonical FROM authors WHERE LOWER(name) = LOWER(NEW.author);
+
+        IF canonical IS NULL THEN
+            SELECT a.name INTO canonical
+            FROM author_aliases al
+            JOIN authors a ON a.id = al.author_id
+            WHERE LOWER(al.alias) = LOWER(NEW.author);
+        END IF;
+
+        IF canonical IS NOT NULL THEN
+            NEW.author := canonical;
+        ELSE
+            INSERT INTO authors (name) VALUES (NEW.author) ON CONFLICT DO NOTHING;
+        END IF;
+
+        SELECT id, name INTO NEW.author_id, NEW.author FROM authors WHERE LOWER(name) = LOWER(NEW.author);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
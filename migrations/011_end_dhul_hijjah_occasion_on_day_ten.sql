@@ -0,0 +1,5 @@
+-- The first ten days of Dhul Hijjah end on the 10th, as 004 now installs
+-- them; databases migrated before keep running until the 13th otherwise
+UPDATE occasions
+SET end_day = 10
+WHERE name = 'First ten days of Dhul Hijjah' AND end_month = 12 AND end_day = 13;
@@ -0,0 +1,28 @@
+-- normalize_arabic() composes its input to NFC first, like arabic.Fold, so
+-- text stored in decomposed form (e.g. alef + combining hamza, or e +
+-- combining acute in a translation) folds to the same search text as the
+-- queries matched against it. normalize() needs PostgreSQL 13 or later and a
+-- UTF8 database.
+
+-- search_text is dropped first so that it is recomputed with the new
+-- function; its index goes with it
+ALTER TABLE quotes DROP COLUMN IF EXISTS search_text;
+
+-- Compose to NFC, strip tashkeel, Quranic marks and tatweel, unify
+-- alef/hamza forms, taa marbuta and alef maqsura, and lower-case. Keep in
+-- sync with arabic.Fold in internal/arabic.
+CREATE OR REPLACE FUNCTION normalize_arabic(input TEXT)
+RETURNS TEXT AS $$
+    SELECT lower(translate(
+        regexp_replace(normalize(input, NFC), '[\u064B-\u065F\u0670\u06D6-\u06ED\u0640]', '', 'g'),
+        'أإآٱؤئةى',
+        'ااااويهي'
+    ))
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE;
+
+ALTER TABLE quotes ADD COLUMN search_text TEXT
+    GENERATED ALWAYS AS (
+        normalize_arabic(text_arabic || E'\n' || COALESCE(text_latin, '') || E'\n' || COALESCE(translation, ''))
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_quotes_search_text ON quotes USING GIN (search_text gin_trgm_ops);
@@ -0,0 +1,13 @@
+-- Quotes are now stored with the category spelled as in the taxonomy or, for
+-- categories outside it, as their tag; respell the ones written as typed
+-- before, so that /categories and the facets list each category once
+UPDATE quotes q
+SET category = c.name
+FROM categories c
+WHERE LOWER(q.category) = LOWER(c.name) AND q.category <> c.name;
+
+UPDATE quotes q
+SET category = t.name
+FROM tags t
+WHERE LOWER(q.category) = LOWER(t.name) AND q.category <> t.name
+  AND NOT EXISTS (SELECT 1 FROM categories c WHERE LOWER(c.name) = LOWER(q.category));
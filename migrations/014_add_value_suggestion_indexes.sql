@@ -0,0 +1,12 @@
+-- /authors/suggest, /categories/suggest and /sources/suggest match word
+-- prefixes of normalize_arabic() of these columns with LIKE patterns that may
+-- start with a wildcard; trigram indexes on the same expressions spare a
+-- sequential scan and a function call per row on every keystroke. The
+-- expressions must stay identical to valueColumns in
+-- internal/database/values.go.
+CREATE INDEX IF NOT EXISTS idx_quotes_author_normalized
+    ON quotes USING GIN (normalize_arabic(author) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_quotes_category_normalized
+    ON quotes USING GIN (normalize_arabic(COALESCE(category, '')) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_quotes_source_normalized
+    ON quotes USING GIN (normalize_arabic(COALESCE(source, '')) gin_trgm_ops);